@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gamejolt/cli/pkg/api"
+	"github.com/gamejolt/cli/pkg/api/models"
+	"github.com/gamejolt/cli/pkg/api/releases"
+	"github.com/gamejolt/cli/pkg/ui"
+
+	semver "github.com/blang/semver/v4"
+)
+
+// BuildsCommand is the builds command, which groups the build management commands
+type BuildsCommand struct {
+	List   BuildsListCommand   `command:"list" description:"List the builds of a release"`
+	Delete BuildsDeleteCommand `command:"delete" description:"Remove a build from a release"`
+}
+
+// BuildsListCommand is the builds list command
+type BuildsListCommand struct {
+	PackageOptions
+	Args ReleaseArgs `positional-args:"1" required:"1"`
+}
+
+// Execute runs the builds list command
+func (c *BuildsListCommand) Execute(args []string) error {
+	apiClient, game, gamePackage, release, err := GetReleaseParams(&c.PackageOptions, c.Args.Version)
+	if err != nil {
+		return err
+	}
+
+	releaseBuilds, err := ListAllReleaseBuilds(apiClient, game.ID, gamePackage.ID, release.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(releaseBuilds) == 0 {
+		ui.Info("Release %s has no builds yet\n", release.Version)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tFILE\tSIZE\tTYPE\tPLATFORMS\tSTATUS")
+	for _, build := range releaseBuilds {
+		filename, filesize := "-", int64(0)
+		if build.File != nil {
+			filename, filesize = build.File.Filename, build.File.Filesize
+		}
+
+		platforms := strings.Join(BuildPlatforms(&build), ",")
+		if platforms == "" {
+			platforms = "-"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\t%s\n", build.ID, filename, filesize, build.Type, platforms, build.Status)
+	}
+	return w.Flush()
+}
+
+// BuildsDeleteCommand is the builds delete command
+type BuildsDeleteCommand struct {
+	PackageOptions
+	Yes  bool `short:"y" long:"yes" description:"Do not ask for confirmation"`
+	Args struct {
+		BuildID string `positional-arg-name:"BUILD-ID" description:"The ID of the build to remove, as shown by 'gjpush builds list'"`
+	} `positional-args:"1" required:"1"`
+}
+
+// Execute runs the builds delete command
+func (c *BuildsDeleteCommand) Execute(args []string) error {
+	buildID, err := strconv.Atoi(c.Args.BuildID)
+	if err != nil || buildID < 1 {
+		return withExitCode(ExitUsage, errors.New("Oh no, invalid build ID - expected a positive integer"))
+	}
+
+	apiClient, game, gamePackage, err := GetPackageParams(&c.PackageOptions)
+	if err != nil {
+		return err
+	}
+
+	if !c.Yes {
+		confirmed, err := Confirm(fmt.Sprintf("Remove build %d from %s?", buildID, gamePackage.Title))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			ui.Info("Not removing anything\n")
+			return nil
+		}
+	}
+
+	if err = apiClient.BuildDelete(game.ID, gamePackage.ID, buildID); err != nil {
+		return err
+	}
+
+	ui.Success("Removed build %d\n", buildID)
+	return nil
+}
+
+// BuildPlatforms returns the platforms a build is for
+func BuildPlatforms(build *models.GameBuild) []string {
+	platforms := []string{}
+	for _, platform := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"windows", build.Windows},
+		{"windows_64", build.Windows64},
+		{"mac", build.Mac},
+		{"mac_64", build.Mac64},
+		{"linux", build.Linux},
+		{"linux_64", build.Linux64},
+		{"other", build.Other},
+	} {
+		if platform.enabled {
+			platforms = append(platforms, platform.name)
+		}
+	}
+	return platforms
+}
+
+// ReplacedBuilds finds the builds of a release that a new build replaces:
+// builds of a file with the same name, or builds for any of the same platforms.
+// The new build may be nil if it wasn't uploaded yet, in which case only the filename is matched.
+func ReplacedBuilds(apiClient *api.Client, game *models.Game, gamePackage *models.GamePackage, releaseSemver *semver.Version, newBuild *models.GameBuild, path string) ([]models.GameBuild, error) {
+	release, err := FindRelease(apiClient, game.ID, gamePackage.ID, releaseSemver)
+	if err != nil {
+		// Nothing to replace in a release that doesn't exist yet
+		if ExitCode(err) == ExitNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	releaseBuilds, err := ListAllReleaseBuilds(apiClient, game.ID, gamePackage.ID, release.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	newPlatforms := map[string]bool{}
+	if newBuild != nil {
+		for _, platform := range BuildPlatforms(newBuild) {
+			newPlatforms[platform] = true
+		}
+	}
+
+	replaced := []models.GameBuild{}
+	for _, build := range releaseBuilds {
+		if newBuild != nil && build.ID == newBuild.ID {
+			continue
+		}
+
+		matches := build.File != nil && build.File.Filename == filepath.Base(path)
+		for _, platform := range BuildPlatforms(&build) {
+			matches = matches || newPlatforms[platform]
+		}
+
+		if matches {
+			replaced = append(replaced, build)
+		}
+	}
+
+	return replaced, nil
+}
+
+// ListAllReleaseBuilds lists the builds of a release, going through every page
+func ListAllReleaseBuilds(apiClient *api.Client, gameID, packageID, releaseID int) ([]models.GameBuild, error) {
+	result := []models.GameBuild{}
+	for page := 1; ; page++ {
+		releaseBuilds, err := apiClient.ReleaseBuilds(releaseID, &releases.ListBuildsOptions{GameID: gameID, PackageID: packageID, Page: page})
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, releaseBuilds.Builds...)
+		if len(releaseBuilds.Builds) == 0 || releaseBuilds.PerPage <= 0 || len(result) >= releaseBuilds.Total {
+			return result, nil
+		}
+	}
+}
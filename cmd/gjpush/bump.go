@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gamejolt/cli/pkg/api"
+
+	semver "github.com/blang/semver/v4"
+)
+
+// LatestRelease finds the highest release version of a game package to bump
+func LatestRelease(apiClient *api.Client, gameID, packageID int) (*semver.Version, error) {
+	latest, err := findLatestRelease(apiClient, gameID, packageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if latest == nil {
+		return nil, withExitCode(ExitNotFound, errors.New("There are no releases to bump yet. Pass the first release version with -r"))
+	}
+	return latest, nil
+}
+
+// findLatestRelease finds the highest release version of a game package. Returns nil if the package has no releases yet.
+func findLatestRelease(apiClient *api.Client, gameID, packageID int) (*semver.Version, error) {
+	gameReleases, err := ListAllReleases(apiClient, gameID, packageID)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *semver.Version
+	for _, release := range gameReleases {
+		releaseSemver, err := semver.Parse(release.Version)
+		if err != nil {
+			continue
+		}
+		if latest == nil || releaseSemver.GT(*latest) {
+			latest = &releaseSemver
+		}
+	}
+
+	return latest, nil
+}
+
+// BumpVersion computes the version that follows the given one.
+// Part is one of major, minor, patch or prerelease.
+//
+// Bumping a prerelease works like npm version does:
+// 1.4.0-nightly.3 bumps to 1.4.0-nightly.4 as a prerelease, and to 1.4.0 as a patch.
+// 1.4.0 bumps to 1.4.1-0 as a prerelease.
+func BumpVersion(version semver.Version, part string) (semver.Version, error) {
+	next := semver.Version{Major: version.Major, Minor: version.Minor, Patch: version.Patch}
+	isPrerelease := len(version.Pre) > 0
+
+	switch part {
+	case "major":
+		if !isPrerelease || version.Minor != 0 || version.Patch != 0 {
+			next.Major++
+			next.Minor = 0
+			next.Patch = 0
+		}
+	case "minor":
+		if !isPrerelease || version.Patch != 0 {
+			next.Minor++
+			next.Patch = 0
+		}
+	case "patch":
+		if !isPrerelease {
+			next.Patch++
+		}
+	case "prerelease":
+		if !isPrerelease {
+			next.Patch++
+			next.Pre = []semver.PRVersion{{VersionNum: 0, IsNum: true}}
+			break
+		}
+
+		next.Pre = append([]semver.PRVersion{}, version.Pre...)
+		last := &next.Pre[len(next.Pre)-1]
+		if last.IsNum {
+			last.VersionNum++
+		} else {
+			next.Pre = append(next.Pre, semver.PRVersion{VersionNum: 0, IsNum: true})
+		}
+	default:
+		return semver.Version{}, fmt.Errorf("Unknown version part to bump: %s", part)
+	}
+
+	return next, nil
+}
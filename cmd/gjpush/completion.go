@@ -0,0 +1,301 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gamejolt/cli/pkg/api"
+	"github.com/gamejolt/cli/pkg/project"
+
+	flags "github.com/jessevdk/go-flags"
+)
+
+const (
+	// completionCacheTTL is how long the games and packages suggested by completion are cached for
+	completionCacheTTL = 10 * time.Minute
+
+	// completionTimeout is how long completion may wait for the api before falling back to the cache
+	completionTimeout = 2 * time.Second
+)
+
+// completionScripts are the completion scripts for each supported shell.
+// They run gjpush with GO_FLAGS_COMPLETION set, which makes it print the completions for the given arguments instead.
+var completionScripts = map[string]string{
+	"bash": `_{{name}}() {
+    local args=("${COMP_WORDS[@]:1:$COMP_CWORD}")
+    local IFS=$'\n'
+    COMPREPLY=($(GO_FLAGS_COMPLETION=1 "${COMP_WORDS[0]}" "${args[@]}" 2>/dev/null))
+    return 0
+}
+complete -o default -F _{{name}} {{name}}
+`,
+	"zsh": `#compdef {{name}}
+_{{name}}() {
+    local -a completions
+    local line
+    for line in "${(@f)$(GO_FLAGS_COMPLETION=descriptions "${words[1]}" "${(@)words[2,$CURRENT]}" 2>/dev/null)}"; do
+        [[ -n "$line" ]] && completions+=("${${line%%$'\t'*}//:/\\:}:${line#*$'\t'}")
+    done
+    if (( ${#completions} )); then
+        _describe '{{name}}' completions
+    else
+        _files
+    fi
+}
+compdef _{{name}} {{name}}
+`,
+	"fish": `function __{{name}}_complete
+    set -l args (commandline -opc)
+    set -e args[1]
+    GO_FLAGS_COMPLETION=descriptions command {{name}} $args (commandline -ct) 2>/dev/null
+end
+complete -c {{name}} -a '(__{{name}}_complete)'
+`,
+}
+
+// completionContext holds the options given so far on the command line being completed
+var completionContext struct {
+	Token  string
+	GameID string
+}
+
+// CompletionCommand is the completion command, which prints a shell completion script
+type CompletionCommand struct {
+	Args struct {
+		Shell string `positional-arg-name:"SHELL" choice:"bash" choice:"zsh" choice:"fish" description:"The shell to print the completion script for: bash, zsh or fish"`
+	} `positional-args:"1" required:"1"`
+}
+
+// Execute runs the completion command
+func (c *CompletionCommand) Execute(args []string) error {
+	if len(args) > 0 {
+		return withExitCode(ExitUsage, errors.New("Too many arguments!"))
+	}
+
+	script, ok := completionScripts[c.Args.Shell]
+	if !ok {
+		return withExitCode(ExitUsage, fmt.Errorf("Unsupported shell %s, expected bash, zsh or fish", c.Args.Shell))
+	}
+
+	fmt.Print(strings.Replace(script, "{{name}}", project.Name, -1))
+	return nil
+}
+
+// GameIDValue is the value of the game option. It completes to the games of the user.
+type GameIDValue string
+
+// Complete suggests the IDs of the user's games
+func (v *GameIDValue) Complete(match string) []flags.Completion {
+	cache := loadCompletionCache()
+	games, ok := cache.Games[""]
+	if !ok || games.stale() {
+		if fetched, err := fetchCompletionGames(); err == nil {
+			games = fetched
+			cache.Games[""] = games
+			cache.save()
+		}
+	}
+
+	return games.complete(match)
+}
+
+// PackageIDValue is the value of the package option. It completes to the packages of the game given with the game option.
+type PackageIDValue string
+
+// Complete suggests the IDs of the packages of the chosen game
+func (v *PackageIDValue) Complete(match string) []flags.Completion {
+	gameID, _, err := parseID(completionContext.GameID)
+	if gameID == 0 && err == nil {
+		gameID, _, _ = parseGameURL(completionContext.GameID)
+	}
+	if gameID == 0 {
+		return nil
+	}
+
+	cache := loadCompletionCache()
+	cacheKey := strconv.Itoa(gameID)
+	gamePackages, ok := cache.Packages[cacheKey]
+	if !ok || gamePackages.stale() {
+		if fetched, err := fetchCompletionPackages(gameID); err == nil {
+			gamePackages = fetched
+			cache.Packages[cacheKey] = gamePackages
+			cache.save()
+		}
+	}
+
+	return gamePackages.complete(match)
+}
+
+// completionItems are cached completion suggestions
+type completionItems struct {
+	FetchedAt int64              `json:"fetched_at"`
+	Items     []flags.Completion `json:"items"`
+}
+
+func (c *completionItems) stale() bool {
+	return time.Since(time.Unix(c.FetchedAt, 0)) >= completionCacheTTL
+}
+
+func (c *completionItems) complete(match string) []flags.Completion {
+	result := []flags.Completion{}
+	for _, item := range c.Items {
+		if strings.HasPrefix(item.Item, match) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// completionCache is the structure of the completion cache located in ~/.gj/completion-cache.json.
+// Games are keyed by an empty string, packages by their game ID.
+type completionCache struct {
+	Games    map[string]completionItems `json:"games"`
+	Packages map[string]completionItems `json:"packages"`
+
+	path string
+}
+
+func loadCompletionCache() *completionCache {
+	cache := &completionCache{}
+	if path, err := gjPath("completion-cache.json"); err == nil {
+		cache.path = path
+		if bytes, err := ioutil.ReadFile(path); err == nil {
+			json.Unmarshal(bytes, cache)
+		}
+	}
+
+	if cache.Games == nil {
+		cache.Games = map[string]completionItems{}
+	}
+	if cache.Packages == nil {
+		cache.Packages = map[string]completionItems{}
+	}
+	return cache
+}
+
+func (c *completionCache) save() {
+	if c.path == "" {
+		return
+	}
+
+	bytes, err := json.Marshal(c)
+	if err != nil || os.MkdirAll(filepath.Dir(c.path), 0755) != nil {
+		return
+	}
+
+	// The cache lists the user's private games, keep it private too
+	ioutil.WriteFile(c.path, bytes, 0600)
+}
+
+func fetchCompletionGames() (completionItems, error) {
+	apiClient, err := newCompletionAPIClient()
+	if err != nil {
+		return completionItems{}, err
+	}
+
+	games, err := ListAllGames(apiClient)
+	if err != nil {
+		return completionItems{}, err
+	}
+
+	result := completionItems{FetchedAt: time.Now().Unix()}
+	for _, game := range games {
+		result.Items = append(result.Items, flags.Completion{Item: strconv.Itoa(game.ID), Description: game.Title})
+	}
+	return result, nil
+}
+
+func fetchCompletionPackages(gameID int) (completionItems, error) {
+	apiClient, err := newCompletionAPIClient()
+	if err != nil {
+		return completionItems{}, err
+	}
+
+	gamePackages, err := ListAllPackages(apiClient, gameID)
+	if err != nil {
+		return completionItems{}, err
+	}
+
+	result := completionItems{FetchedAt: time.Now().Unix()}
+	for _, gamePackage := range gamePackages {
+		result.Items = append(result.Items, flags.Completion{Item: strconv.Itoa(gamePackage.ID), Description: gamePackage.Title})
+	}
+	return result, nil
+}
+
+// newCompletionAPIClient creates an api client that gives up quickly, so that completion never hangs
+func newCompletionAPIClient() (*api.Client, error) {
+	token := completionContext.Token
+	if token == "" {
+		// Anything printed here would be offered as a completion
+		token, _, _ = readTokenFallback()
+	}
+	if token == "" {
+		return nil, errors.New("No token to fetch completions with")
+	}
+
+	noWait := time.Duration(0)
+	completionOpts := &Options{}
+	completionOpts.Network.ConnectTimeout = completionTimeout
+	completionOpts.Network.ReadTimeout = completionTimeout
+	completionOpts.Network.RateLimitWait = &noWait
+
+	return NewAPIClient(completionOpts, token)
+}
+
+// prepareCompletion remembers the options completion depends on, and makes push the default command for the arguments
+// before the one being completed.
+func prepareCompletion(parser *flags.Parser, args []string) []string {
+	completionContext.Token = findOptionValue(args, "t", "token")
+	completionContext.GameID = findOptionValue(args, "g", "game")
+
+	if len(args) == 0 {
+		return args
+	}
+
+	last := len(args) - 1
+	return append(withDefaultCommand(parser, args[:last]), args[last])
+}
+
+// findOptionValue finds the value of an option among the given arguments
+func findOptionValue(args []string, short, long string) string {
+	for i, arg := range args {
+		var value string
+		switch {
+		case arg == "-"+short || arg == "--"+long:
+			if i+1 < len(args) {
+				value = args[i+1]
+			}
+		case strings.HasPrefix(arg, "--"+long+"="):
+			value = strings.TrimPrefix(arg, "--"+long+"=")
+		case strings.HasPrefix(arg, "-"+short) && !strings.HasPrefix(arg, "--"):
+			value = strings.TrimPrefix(arg, "-"+short)
+		}
+
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// printCompletions prints the completions and exits.
+// Completions are printed along with their descriptions separated by a tab if GO_FLAGS_COMPLETION is set to "descriptions".
+func printCompletions(items []flags.Completion) {
+	withDescriptions := os.Getenv("GO_FLAGS_COMPLETION") == "descriptions"
+	for _, item := range items {
+		if withDescriptions {
+			fmt.Printf("%s\t%s\n", item.Item, strings.Join(strings.Fields(item.Description), " "))
+		} else {
+			fmt.Println(item.Item)
+		}
+	}
+	Exit(ExitOK)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/gamejolt/cli/pkg/api"
+	"github.com/gamejolt/cli/pkg/api/builds"
+	"github.com/gamejolt/cli/pkg/api/models"
+	"github.com/gamejolt/cli/pkg/inspect"
+	"github.com/gamejolt/cli/pkg/ui"
+)
+
+// platformNames are the display names of the os fields of a build
+var platformNames = map[string]string{
+	"windows":    "Windows 32-bit",
+	"windows_64": "Windows 64-bit",
+	"mac":        "macOS 32-bit",
+	"mac_64":     "macOS 64-bit",
+	"linux":      "Linux 32-bit",
+	"linux_64":   "Linux 64-bit",
+	"other":      "Other",
+}
+
+// DetectPlatforms detects the platforms a desktop build runs on and its main executables, and reports them.
+// Returns the build fields to set, or nil if nothing was detected.
+func DetectPlatforms(path string) *builds.UpdateOptions {
+	binaries, err := inspect.FindBinaries(path)
+	if err != nil {
+		ui.Warn("Failed to detect the platforms of the build: %s\n", err.Error())
+		return nil
+	}
+
+	platforms := inspect.Platforms(binaries)
+	if len(platforms) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(platforms))
+	for i, platform := range platforms {
+		names[i] = platformNames[platform]
+	}
+	ui.Info("Detected platforms: %s\n", strings.Join(names, ", "))
+
+	launchOptions := inspect.LaunchOptions(binaries)
+	for _, launchOption := range launchOptions {
+		ui.Info("Detected main executable for %s: %s\n", platformNames[launchOption.OS], launchOption.ExecutablePath)
+	}
+
+	return PlatformUpdate(platforms, launchOptions)
+}
+
+// PlatformUpdate returns the build fields that make a build run on exactly the given platforms, with the given launch options
+func PlatformUpdate(platforms []string, launchOptions []models.GameBuildLaunchOption) *builds.UpdateOptions {
+	enabled := map[string]bool{}
+	for _, platform := range platforms {
+		enabled[platform] = true
+	}
+
+	flag := func(platform string) *bool {
+		value := enabled[platform]
+		return &value
+	}
+
+	return &builds.UpdateOptions{
+		Windows:       flag("windows"),
+		Windows64:     flag("windows_64"),
+		Mac:           flag("mac"),
+		Mac64:         flag("mac_64"),
+		Linux:         flag("linux"),
+		Linux64:       flag("linux_64"),
+		Other:         flag("other"),
+		LaunchOptions: launchOptions,
+	}
+}
+
+// DetectEmbedSize detects the size a zipped browser build is meant to be embedded at, and reports it.
+// The given width and height override the detected ones. Returns the build fields to set, or nil if there is no size to set.
+func DetectEmbedSize(path string, width, height *int) *builds.UpdateOptions {
+	if (width == nil || height == nil) && strings.EqualFold(filepath.Ext(path), ".zip") {
+		size, err := inspect.DetectEmbedSize(path)
+		if err != nil {
+			ui.Warn("Failed to detect the embed size of the build: %s\n", err.Error())
+		} else if size != nil {
+			ui.Info("Detected embed size: %dx%d (from %s)\n\n", size.Width, size.Height, size.Source)
+			if width == nil {
+				width = &size.Width
+			}
+			if height == nil {
+				height = &size.Height
+			}
+		}
+	}
+
+	if width == nil && height == nil {
+		return nil
+	}
+	return &builds.UpdateOptions{EmbedWidth: width, EmbedHeight: height}
+}
+
+// UpdateBuild sets fields of a freshly uploaded build. Returns the updated build.
+func UpdateBuild(apiClient *api.Client, game *models.Game, gamePackage *models.GamePackage, build *models.GameBuild, options *builds.UpdateOptions) (*models.GameBuild, error) {
+	ui.Info("Updating the build settings (Build ID: %d) ...\n", build.ID)
+	updated, err := apiClient.BuildUpdate(game.ID, gamePackage.ID, build.ID, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if updated == nil {
+		return build, nil
+	}
+	return updated, nil
+}
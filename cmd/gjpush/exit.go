@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"net"
+
+	apiErrors "github.com/gamejolt/cli/pkg/api/errors"
+	"github.com/gamejolt/cli/pkg/update"
+)
+
+// Exit codes gjpush exits with, grouped by failure class so that scripts can decide whether to retry.
+// These are part of the public interface of the tool, so existing codes must never change meaning.
+const (
+	// ExitOK is the exit code for success.
+	ExitOK = 0
+
+	// ExitUnknown is the exit code for any failure that doesn't fall into a more specific class.
+	ExitUnknown = 1
+
+	// ExitUsage is the exit code for invalid command line options or input.
+	ExitUsage = 2
+
+	// ExitAuth is the exit code for a missing or invalid authentication token.
+	ExitAuth = 3
+
+	// ExitInvalidFields is the exit code for requests the server rejected because of missing or invalid fields.
+	ExitInvalidFields = 4
+
+	// ExitNotFound is the exit code for a game, package or release that couldn't be found.
+	ExitNotFound = 5
+
+	// ExitCantAttachBuild is the exit code for a build that was uploaded but couldn't be attached to the package.
+	ExitCantAttachBuild = 6
+
+	// ExitServer is the exit code for failures on Game Jolt's end, for example failing to process an uploaded file.
+	ExitServer = 7
+
+	// ExitNetwork is the exit code for transient network failures: connection errors, timeouts, rate limits and outages.
+	// These are usually worth retrying.
+	ExitNetwork = 8
+
+	// ExitUnexpectedResponse is the exit code for responses that are not valid api payloads.
+	ExitUnexpectedResponse = 9
+
+	// ExitFileNotFound is the exit code for a file to upload that doesn't exist or can't be read.
+	ExitFileNotFound = 10
+
+	// ExitChecksumFailed is the exit code for failing to calculate the checksum of the file to upload.
+	ExitChecksumFailed = 11
+
+	// ExitUploadFailed is the exit code for an upload that failed midway, for example because the file changed while uploading.
+	ExitUploadFailed = 12
+
+	// ExitInvalidBuild is the exit code for a build that was refused because checking it found issues.
+	ExitInvalidBuild = 13
+
+	// ExitInterrupted is the exit code for when the user interrupts the program, e.g. with Ctrl+C.
+	ExitInterrupted = 130
+)
+
+// exitError is an error that knows which exit code the program should exit with
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode attaches an exit code to an error
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code, err}
+}
+
+// ExitCode returns the exit code matching the failure class of the given error
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+
+	var apiErr *apiErrors.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code() {
+		case apiErrors.MissingAuthorization, apiErrors.InvalidAuthorization:
+			return ExitAuth
+		case apiErrors.InvalidFields:
+			return ExitInvalidFields
+		case apiErrors.CantAttachBuild:
+			return ExitCantAttachBuild
+		case apiErrors.CantCleanTempFile, apiErrors.UnknownError:
+			return ExitServer
+		case apiErrors.ChunkChecksumMismatch:
+			return ExitUploadFailed
+		}
+		return ExitUnknown
+	}
+
+	switch {
+	case errors.Is(err, apiErrors.ErrUnauthorized):
+		return ExitAuth
+	case errors.Is(err, apiErrors.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, apiErrors.ErrRateLimited), errors.Is(err, apiErrors.ErrServiceUnavailable), errors.Is(err, apiErrors.ErrBadGateway):
+		return ExitNetwork
+	case errors.Is(err, apiErrors.ErrServer):
+		return ExitServer
+	case errors.Is(err, apiErrors.ErrUnexpectedResponse):
+		return ExitUnexpectedResponse
+	case errors.Is(err, update.ErrChecksumMismatch):
+		return ExitChecksumFailed
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ExitNetwork
+	}
+
+	return ExitUnknown
+}
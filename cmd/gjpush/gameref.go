@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gamejolt/cli/pkg/api"
+	"github.com/gamejolt/cli/pkg/api/games"
+	"github.com/gamejolt/cli/pkg/api/models"
+)
+
+// slugRegex matches the characters that are replaced when turning a title into a slug
+var slugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// parseID parses an ID given as a number. Returns false if the value isn't a number.
+func parseID(value string) (id int, isID bool, err error) {
+	id, err = strconv.Atoi(value)
+	if err != nil {
+		return 0, false, nil
+	}
+	if id < 1 {
+		return 0, true, errors.New("expected a positive integer")
+	}
+	return id, true, nil
+}
+
+// parseGameURL extracts the game and package IDs from a Game Jolt url, for example a dashboard url like
+// https://gamejolt.com/dashboard/games/123/packages/456/edit or a game page url like https://gamejolt.com/games/slug/123.
+// IDs that aren't in the url are returned as 0. Returns false if the value isn't a url.
+func parseGameURL(value string) (gameID, packageID int, isURL bool) {
+	if !strings.Contains(value, "://") {
+		if !strings.HasPrefix(value, "gamejolt.com/") && !strings.HasPrefix(value, "www.gamejolt.com/") {
+			return 0, 0, false
+		}
+		value = "https://" + value
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" {
+		return 0, 0, false
+	}
+
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		switch segments[i] {
+		case "games":
+			if id, err := strconv.Atoi(segments[i+1]); err == nil {
+				gameID = id
+			} else if i+2 < len(segments) {
+				if id, err := strconv.Atoi(segments[i+2]); err == nil {
+					gameID = id
+				}
+			}
+		case "packages":
+			if id, err := strconv.Atoi(segments[i+1]); err == nil {
+				packageID = id
+			}
+		}
+	}
+
+	return gameID, packageID, true
+}
+
+// LookupGame gets the game the options select. Games given by their title or slug are looked up among the user's games.
+// If no game was given, it will be prompted.
+func LookupGame(apiClient *api.Client, gameOpts *GameOptions, gameID int) (*models.Game, error) {
+	if query := gameOpts.GameQuery(); gameID == 0 && query != "" {
+		game, err := FindGame(apiClient, query)
+		if err != nil {
+			return nil, err
+		}
+		gameID = game.ID
+	}
+
+	return GetGame(apiClient, gameID)
+}
+
+// FindGame finds one of the user's games by its title or slug. Titles match case insensitively.
+// If no game matches exactly, games with a title containing the query match instead.
+func FindGame(apiClient *api.Client, query string) (*models.Game, error) {
+	userGames, err := ListAllGames(apiClient)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []models.Game{}
+	for _, game := range userGames {
+		if strings.EqualFold(game.Title, query) || gameSlug(&game) == strings.ToLower(query) {
+			matches = append(matches, game)
+		}
+	}
+
+	if len(matches) == 0 {
+		for _, game := range userGames {
+			if strings.Contains(strings.ToLower(game.Title), strings.ToLower(query)) {
+				matches = append(matches, game)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, withExitCode(ExitNotFound, fmt.Errorf("None of your games match %s", query))
+	case 1:
+		return &matches[0], nil
+	}
+
+	titles := make([]string, len(matches))
+	for i, game := range matches {
+		titles[i] = fmt.Sprintf("%s (ID: %d)", game.Title, game.ID)
+	}
+	return nil, withExitCode(ExitUsage, fmt.Errorf("Several games match %s, pass the game ID instead: %s", query, strings.Join(titles, ", ")))
+}
+
+// ListAllGames gets all the games of the user, going through all the pages
+func ListAllGames(apiClient *api.Client) ([]models.Game, error) {
+	result := []models.Game{}
+	for page := 1; ; page++ {
+		userGames, err := apiClient.Games(&games.ListOptions{Page: page})
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, userGames.Games...)
+		if len(userGames.Games) == 0 || userGames.PerPage <= 0 || len(result) >= userGames.Total {
+			return result, nil
+		}
+	}
+}
+
+// gameSlug returns the slug of a game, as found in its url or made from its title
+func gameSlug(game *models.Game) string {
+	if parsed, err := url.Parse(game.URL); err == nil {
+		segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+		for i := 0; i+1 < len(segments); i++ {
+			if segments[i] == "games" {
+				if _, err := strconv.Atoi(segments[i+1]); err != nil {
+					return strings.ToLower(segments[i+1])
+				}
+			}
+		}
+	}
+
+	return strings.Trim(slugRegex.ReplaceAllString(strings.ToLower(game.Title), "-"), "-")
+}
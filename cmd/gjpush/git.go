@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	semver "github.com/blang/semver/v4"
+)
+
+// describeRegex matches the output of git describe --long, for example "v1.2.3-5-g1a2b3c4"
+var describeRegex = regexp.MustCompile(`^(.+)-(\d+)-g([0-9a-f]+)$`)
+
+// GitRelease gets the release version from the latest git tag reachable from the current commit in the working directory.
+// Commits made after the tag are added as build metadata, for example "v1.2.3" 5 commits later becomes "1.2.3+5.g1a2b3c4".
+func GitRelease() (*semver.Version, error) {
+	cmd := exec.Command("git", "describe", "--tags", "--long")
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			err = errors.New(message)
+		}
+		return nil, withExitCode(ExitUsage, fmt.Errorf("Failed to get the release version from git: %w", err))
+	}
+
+	version, err := parseGitDescribe(strings.TrimSpace(string(output)))
+	if err != nil {
+		return nil, withExitCode(ExitUsage, err)
+	}
+	return version, nil
+}
+
+func parseGitDescribe(description string) (*semver.Version, error) {
+	match := describeRegex.FindStringSubmatch(description)
+	if match == nil {
+		return nil, fmt.Errorf("Unexpected git describe output: %s", description)
+	}
+
+	tag, distance, hash := match[1], match[2], match[3]
+	version, err := semver.ParseTolerant(tag)
+	if err != nil {
+		return nil, fmt.Errorf("The git tag %s is not a valid semver. Check out https://semver.org", tag)
+	}
+
+	version.Build = nil
+	if distance != "0" {
+		version.Build = []string{distance, "g" + hash}
+	}
+	return &version, nil
+}
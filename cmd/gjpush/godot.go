@@ -0,0 +1,361 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gamejolt/cli/config"
+	"github.com/gamejolt/cli/pkg/api"
+	"github.com/gamejolt/cli/pkg/api/builds"
+	"github.com/gamejolt/cli/pkg/api/models"
+	"github.com/gamejolt/cli/pkg/fs"
+	"github.com/gamejolt/cli/pkg/godot"
+	"github.com/gamejolt/cli/pkg/ui"
+
+	semver "github.com/blang/semver/v4"
+)
+
+// godotPackagedExts are the extensions of exports that are pushed as they are. Any other export is pushed by zipping its folder.
+var godotPackagedExts = []string{".zip", ".dmg", ".apk", ".aab", ".ipa", ".appx"}
+
+// GodotCommand is the godot command, which pushes the exports of a Godot project
+type GodotCommand struct {
+	PackageOptions
+	ReleaseOptions
+	Project     string   `long:"project" value-name:"DIR" default:"." description:"The Godot project folder, containing export_presets.cfg"`
+	Presets     []string `long:"preset" value-name:"NAME" description:"The name of an export preset to push. Can be given several times. By default pushes every preset."`
+	PackagesFor []string `long:"package-for" value-name:"PRESET=PACKAGE" description:"Push the export of a preset to another package than the one given with -p, like Web=123. Can be given several times."`
+	DryRun      bool     `long:"dry-run" description:"Report what would be pushed without uploading anything."`
+	Force       bool     `long:"force" description:"Push browser builds even if checking them found issues."`
+}
+
+// godotExport is an export of a preset, and how to push it
+type godotExport struct {
+	Preset *godot.Preset
+
+	// Path is the file to push, or the folder to zip and push
+	Path  string
+	IsDir bool
+
+	// Platform is the os field of a build the export runs on, or empty for browser builds
+	Platform string
+
+	// Executable is the path of the main executable in the pushed build, if known
+	Executable string
+
+	// PackageID is the package the export is pushed to, or 0 to push it to the package given with -p
+	PackageID int
+}
+
+// Execute runs the godot command
+func (c *GodotCommand) Execute(args []string) error {
+	if len(args) > 0 {
+		return withExitCode(ExitUsage, errors.New("Too many arguments! The godot command takes the project folder with --project"))
+	}
+	if err := c.ValidateRelease(); err != nil {
+		return err
+	}
+
+	exports, err := c.findExports()
+	if err != nil {
+		return err
+	}
+
+	gameID, defaultPackageID, err := c.IDs()
+	if err != nil {
+		return err
+	}
+
+	apiClient, game, err := getGameParams(&c.GameOptions, gameID)
+	if err != nil {
+		return err
+	}
+
+	// The package given with -p is only needed if some export isn't mapped to another one
+	gamePackages := map[int]*models.GamePackage{}
+	for _, export := range exports {
+		if _, ok := gamePackages[export.PackageID]; ok {
+			continue
+		}
+
+		packageID := export.PackageID
+		if packageID == 0 {
+			packageID = defaultPackageID
+		}
+		if gamePackages[export.PackageID], err = GetGamePackage(apiClient, game.ID, packageID); err != nil {
+			return err
+		}
+	}
+
+	// Every export goes to the same release, so it's only picked once. Bumped releases follow the latest release of each package though.
+	releases := map[int]*semver.Version{}
+	releaseKey := func(gamePackage *models.GamePackage) int {
+		if c.Bump == "" {
+			return 0
+		}
+		return gamePackage.ID
+	}
+	for _, export := range exports {
+		gamePackage := gamePackages[export.PackageID]
+		if _, ok := releases[releaseKey(gamePackage)]; ok {
+			continue
+		}
+		if releases[releaseKey(gamePackage)], err = c.GetReleaseVersion(apiClient, game, gamePackage); err != nil {
+			return err
+		}
+	}
+
+	for i, export := range exports {
+		if i > 0 {
+			ui.Info("\n")
+		}
+		gamePackage := gamePackages[export.PackageID]
+		if err = c.push(apiClient, game, gamePackage, releases[releaseKey(gamePackage)], export); err != nil {
+			return fmt.Errorf("Failed to push the %s preset: %w", export.Preset.Name, err)
+		}
+	}
+
+	if !c.DryRun {
+		if len(releases) == 1 {
+			ui.Success("\nPushed %d exports to release %s :D\n", len(exports), releases[releaseKey(gamePackages[exports[0].PackageID])].String())
+		} else {
+			ui.Success("\nPushed %d exports :D\n", len(exports))
+		}
+	}
+	return nil
+}
+
+// findExports reads the export presets of the project, and finds the exports of the presets to push
+func (c *GodotCommand) findExports() ([]godotExport, error) {
+	presets, err := godot.ReadPresets(c.Project)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, withExitCode(ExitFileNotFound, fmt.Errorf("There is no %s in %s. Add export presets in Project > Export first", godot.PresetsFile, c.Project))
+		}
+		return nil, withExitCode(ExitFileNotFound, err)
+	}
+
+	selected, err := selectPresets(presets, c.Presets)
+	if err != nil {
+		return nil, err
+	}
+
+	packageIDs, err := c.presetPackages(presets)
+	if err != nil {
+		return nil, err
+	}
+
+	exports := []godotExport{}
+	dirs := map[string]string{}
+	for i := range selected {
+		export, err := c.findExport(selected[i])
+		if err != nil {
+			return nil, err
+		}
+		export.PackageID = packageIDs[export.Preset.Name]
+
+		// Exports sharing a folder would end up in each other's builds
+		if export.IsDir {
+			if other, ok := dirs[export.Path]; ok {
+				return nil, withExitCode(ExitUsage, fmt.Errorf("The %s and %s presets export to the same folder %s. Export them to separate folders, or to zip files", other, export.Preset.Name, export.Path))
+			}
+			dirs[export.Path] = export.Preset.Name
+		}
+		exports = append(exports, *export)
+	}
+	return exports, nil
+}
+
+// selectPresets returns the presets with the given names, or all of them if no names are given
+func selectPresets(presets []godot.Preset, names []string) ([]*godot.Preset, error) {
+	if len(presets) == 0 {
+		return nil, withExitCode(ExitUsage, errors.New("The project has no export presets. Add them in Project > Export first"))
+	}
+
+	all := make([]string, len(presets))
+	byName := map[string]*godot.Preset{}
+	for i := range presets {
+		all[i] = presets[i].Name
+		byName[strings.ToLower(presets[i].Name)] = &presets[i]
+	}
+
+	if len(names) == 0 {
+		names = all
+	}
+
+	selected := []*godot.Preset{}
+	for _, name := range names {
+		preset, ok := byName[strings.ToLower(name)]
+		if !ok {
+			return nil, withExitCode(ExitUsage, fmt.Errorf("There is no export preset named %s. The presets are: %s", name, strings.Join(all, ", ")))
+		}
+		selected = append(selected, preset)
+	}
+	return selected, nil
+}
+
+// presetPackages reads the packages presets are mapped to with --package-for, keyed by the name of the preset
+func (c *GodotCommand) presetPackages(presets []godot.Preset) (map[string]int, error) {
+	packageIDs := map[string]int{}
+	for _, mapping := range c.PackagesFor {
+		separator := strings.Index(mapping, "=")
+		if separator == -1 {
+			return nil, withExitCode(ExitUsage, fmt.Errorf("Invalid --package-for %s, expected the name of a preset and a package, like Web=123", mapping))
+		}
+
+		names := []string{strings.TrimSpace(mapping[:separator])}
+		selected, err := selectPresets(presets, names)
+		if err != nil {
+			return nil, err
+		}
+
+		packageOpts := PackageOptions{GameOptions: c.GameOptions, PackageIDStr: PackageIDValue(mapping[separator+1:])}
+		_, packageID, err := packageOpts.IDs()
+		if err != nil {
+			return nil, err
+		}
+		if packageID == 0 {
+			return nil, withExitCode(ExitUsage, fmt.Errorf("Invalid --package-for %s, there is no package after the preset name", mapping))
+		}
+		packageIDs[selected[0].Name] = packageID
+	}
+	return packageIDs, nil
+}
+
+// findExport finds the export of a preset on disk, and works out what build it makes
+func (c *GodotCommand) findExport(preset *godot.Preset) (*godotExport, error) {
+	if preset.ExportPath == "" {
+		return nil, withExitCode(ExitUsage, fmt.Errorf("The %s preset has no export path. Set it in Project > Export", preset.Name))
+	}
+
+	path := filepath.Join(c.Project, filepath.FromSlash(strings.TrimPrefix(preset.ExportPath, "res://")))
+	if _, err := os.Stat(path); err != nil {
+		return nil, withExitCode(ExitFileNotFound, fmt.Errorf("The %s preset hasn't been exported yet, %s doesn't exist", preset.Name, path))
+	}
+
+	export := &godotExport{Preset: preset, Path: path, Platform: godotPlatform(preset)}
+	for _, ext := range godotPackagedExts {
+		if strings.EqualFold(filepath.Ext(path), ext) {
+			return export, nil
+		}
+	}
+
+	// Exports made of several files are pushed by zipping their folder, with the exported file as the main executable.
+	// Zipping a folder of the project itself would push the project's sources along with the game.
+	dir := filepath.Dir(path)
+	if isGodotProject(dir) || sameDir(dir, c.Project) {
+		return nil, withExitCode(ExitUsage, fmt.Errorf("The %s preset exports to %s, which holds the Godot project, so pushing it would push the project's files too. Set its export path to a file in a dedicated export folder, like export/%s", preset.Name, dir, filepath.Base(path)))
+	}
+	export.Path, export.IsDir = dir, true
+	if export.Platform != "" {
+		export.Executable = filepath.Base(path)
+	}
+	return export, nil
+}
+
+// isGodotProject returns whether a folder is the root of a Godot project
+func isGodotProject(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "project.godot"))
+	return err == nil
+}
+
+// sameDir returns whether two paths point to the same folder
+func sameDir(a, b string) bool {
+	statA, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	statB, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(statA, statB)
+}
+
+// godotPlatform returns the os field of a build the exports of a preset run on, or an empty string for browser builds.
+// Presets that don't set an architecture default to 64-bit, like Godot does.
+func godotPlatform(preset *godot.Preset) string {
+	is64 := preset.Option("binary_format/64_bits") != "false" && !strings.HasSuffix(preset.Option("binary_format/architecture"), "32")
+
+	switch preset.Platform {
+	case "Web", "HTML5":
+		return ""
+	case "Windows Desktop":
+		if is64 {
+			return "windows_64"
+		}
+		return "windows"
+	case "Linux", "Linux/X11", "Linux/BSD":
+		if is64 {
+			return "linux_64"
+		}
+		return "linux"
+	case "macOS", "Mac OSX":
+		return "mac_64"
+	}
+	return "other"
+}
+
+// push pushes the export of a preset
+func (c *GodotCommand) push(apiClient *api.Client, game *models.Game, gamePackage *models.GamePackage, releaseSemver *semver.Version, export godotExport) error {
+	buildType := platformNames[export.Platform] + " desktop build"
+	if export.Platform == "" {
+		buildType = "browser build"
+	}
+	ui.Info("Pushing the %s preset to the %s package as a %s: %s\n", export.Preset.Name, gamePackage.Title, buildType, export.Path)
+
+	path := export.Path
+	if export.IsDir {
+		ui.Info("Zipping %s ...\n", export.Path)
+		zipPath, cleanup, err := fs.ZipFolderToTemp(export.Path, skipDoNotShip)
+		if err != nil {
+			return fmt.Errorf("Failed to zip the export folder: %w", err)
+		}
+		defer cleanup()
+		path = zipPath
+	}
+
+	browserBuild := export.Platform == ""
+	var buildUpdate *builds.UpdateOptions
+	if browserBuild {
+		if err := CheckBrowserBuild(path, c.Force); err != nil {
+			return err
+		}
+		buildUpdate = DetectEmbedSize(path, nil, nil)
+	} else {
+		launchOptions := []models.GameBuildLaunchOption{}
+		if export.Executable != "" {
+			launchOptions = append(launchOptions, models.GameBuildLaunchOption{OS: export.Platform, ExecutablePath: export.Executable})
+		}
+		buildUpdate = PlatformUpdate([]string{export.Platform}, launchOptions)
+	}
+
+	if c.DryRun {
+		ui.Success("Would push it to release %s\n", releaseSemver.String())
+		return nil
+	}
+
+	filesize, checksum, err := getFileData(path)
+	if err != nil {
+		return err
+	}
+
+	fileStatus, err := apiClient.FileStatus(game.ID, filesize, checksum)
+	if err != nil {
+		return err
+	}
+
+	build, err := StartUpload(apiClient, game, gamePackage, releaseSemver, browserBuild, path, filesize, checksum, fileStatus, config.ChunkSize, false)
+	if err != nil {
+		return err
+	}
+	ui.Success("Upload complete :D\n")
+
+	if buildUpdate != nil && build != nil {
+		_, err = UpdateBuild(apiClient, game, gamePackage, build, buildUpdate)
+	}
+	return err
+}
@@ -2,30 +2,26 @@ package main
 
 import (
 	"bufio"
-	"crypto/md5"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/gamejolt/cli/config"
 	"github.com/gamejolt/cli/pkg/api"
-	"github.com/gamejolt/cli/pkg/api/files"
 	"github.com/gamejolt/cli/pkg/api/models"
 	"github.com/gamejolt/cli/pkg/api/packages"
-	"github.com/gamejolt/cli/pkg/fs"
-	_io "github.com/gamejolt/cli/pkg/io"
+	cliHttp "github.com/gamejolt/cli/pkg/http"
 	"github.com/gamejolt/cli/pkg/project"
 	"github.com/gamejolt/cli/pkg/ui"
 
 	semver "github.com/blang/semver/v4"
-	pb "github.com/cheggaaa/pb/v3"
 	color "github.com/fatih/color"
 	flags "github.com/jessevdk/go-flags"
 	"golang.org/x/term"
@@ -33,75 +29,156 @@ import (
 
 var inReader = bufio.NewReader(os.Stdin)
 
+// opts are the parsed global command line options. Commands read them when they run.
+var opts = &Options{}
+
 func main() {
 	color.Unset()
 	defer color.Unset()
 
-	opts, err := ParseOptions()
+	handleInterrupt()
+
+	command, args, err := ParseOptions()
 	if err != nil {
-		ErrorAndExit("%s\n", err.Error())
+		ErrorAndExit(err)
 	}
 
 	// Help and version are printed in the parse options command, we should be able to just quit here
 	if opts.Help || opts.Version {
-		Exit(0)
+		if opts.invalid {
+			Exit(ExitUsage)
+		}
+		Exit(ExitOK)
 	}
 
-	apiClient, game, gamePackage, releaseSemver, filepath, filesize, checksum, fileStatus, chunkSize, err := GetParams(opts)
-	if err != nil {
-		ErrorAndExit("%s\n", err.Error())
+	if trace, err = openTrace(opts); err != nil {
+		ErrorAndExit(err)
 	}
+	defer closeTrace()
 
-	if fileStatus.Status == "new" {
-		ui.Info("Starting a new upload ...\n")
-	} else if fileStatus.Status == "partial" {
-		if opts.Advanced.NoResume {
-			ui.Info("Aborting existing upload (File ID: %d) ...\n", fileStatus.FileID)
+	updateCheck := StartUpdateCheck(command)
+	if err = command.Execute(args); err != nil {
+		ErrorAndExit(err)
+	}
+	PrintUpdateNotice(updateCheck)
+}
 
-			_, err := apiClient.FileRestart(opts.GameID, filesize, checksum)
-			if err != nil {
-				ErrorAndExit("%s\n", err.Error())
-			}
+// Options is the command line options struct.
+// These are the global options, every command defines its own options on top of these.
+type Options struct {
+	Token    string `short:"t" long:"token" value-name:"TOKEN" description:"Your service API authentication token"`
+	Advanced struct {
+		Verbose       bool   `long:"verbose" description:"Log every request made and response received to stderr. The token is never logged."`
+		Trace         string `long:"trace" value-name:"FILE" description:"Log every request made and response received to a file, for example to attach to a support ticket. The token is never logged."`
+		NoUpdateCheck bool   `long:"no-update-check" description:"Do not check for a new version of gjpush. The check is also skipped on CI."`
+	} `group:"Advanced Options"`
+	Network struct {
+		ConnectTimeout time.Duration  `long:"connect-timeout" value-name:"DURATION" description:"How long to wait for a connection to the server, for example 10s. No timeout by default."`
+		ReadTimeout    time.Duration  `long:"read-timeout" value-name:"DURATION" description:"How long to wait for the server to respond, for example 1m. No timeout by default."`
+		Proxy          string         `long:"proxy" value-name:"URL" description:"The proxy to use, for example http://proxy:8080 or socks5://proxy:1080. Defaults to the HTTP_PROXY/HTTPS_PROXY environment variables."`
+		CACerts        []string       `long:"ca-cert" value-name:"FILE" description:"A PEM encoded CA certificate to trust in addition to the system ones. Can be given multiple times."`
+		ClientCert     string         `long:"client-cert" value-name:"FILE" description:"A PEM encoded client certificate to authenticate with."`
+		ClientKey      string         `long:"client-key" value-name:"FILE" description:"The PEM encoded private key of the client certificate."`
+		RateLimitWait  *time.Duration `long:"rate-limit-wait" value-name:"DURATION" description:"How long in total to wait for the server to lift rate limits before giving up. Defaults to 5m."`
+	} `group:"Network Options"`
+	Help    bool `short:"h" long:"help" description:"Show this help message"`
+	Version bool `short:"v" long:"version" description:"Display the version"`
 
-			ui.Info("Starting a new upload ...\n")
-		} else {
-			ui.Info("Resuming the upload (File ID: %d) ...\n", fileStatus.FileID)
+	Push       PushCommand       `command:"push" description:"Upload a build file. This is the default command, so 'gjpush FILE' works too."`
+	Packages   PackagesCommand   `command:"packages" description:"Manage the packages of a game"`
+	Builds     BuildsCommand     `command:"builds" description:"Manage the builds of a release"`
+	Releases   ReleasesCommand   `command:"releases" description:"Manage the releases of a game package"`
+	Watch      WatchCommand      `command:"watch" description:"Watch a build file or folder, and push it whenever it changes"`
+	Godot      GodotCommand      `command:"godot" description:"Push the exports of a Godot project, one build per export preset"`
+	Completion CompletionCommand `command:"completion" description:"Print the shell completion script for bash, zsh or fish"`
+	SelfUpdate SelfUpdateCommand `command:"self-update" description:"Update gjpush to the latest version"`
+
+	// invalid is set when the options failed to parse and the help is printed instead
+	invalid bool
+}
+
+// GameOptions are the options that select the game a command works with
+type GameOptions struct {
+	GameIDStr GameIDValue `short:"g" long:"game" value-name:"GAME-ID" description:"The game ID, url, title or slug"`
+}
+
+// GameID validates and returns the game ID given as an ID or a url.
+// Returns 0 if it was not given, or if it was given as a title or slug to look up with LookupGame.
+func (o *GameOptions) GameID() (int, error) {
+	value := strings.TrimSpace(string(o.GameIDStr))
+	if value == "" {
+		return 0, nil
+	}
+
+	if gameID, isID, err := parseID(value); isID {
+		if err != nil {
+			return 0, withExitCode(ExitUsage, fmt.Errorf("Oh no, invalid game ID - %s", err.Error()))
 		}
-	} else if fileStatus.Status == "error" {
-		ui.Warn("There was an issue with the previous upload chunk, we have to start over :(\n")
-		ui.Info("Starting a new upload ...\n")
+		return gameID, nil
 	}
 
-	startByte := fileStatus.Start
-	if opts.Advanced.NoResume || fileStatus.Status == "error" {
-		startByte = 0
+	if gameID, _, isURL := parseGameURL(value); isURL {
+		if gameID == 0 {
+			return 0, withExitCode(ExitUsage, errors.New("Oh no, there is no game ID in the url"))
+		}
+		return gameID, nil
 	}
 
-	err = Upload(apiClient, game, gamePackage, releaseSemver, opts.IsBrowser, filepath, filesize, checksum, startByte, chunkSize)
-	if err != nil {
-		ErrorAndExit("%s\n", err.Error())
+	return 0, nil
+}
+
+// GameQuery returns the title or slug the game was given as. Returns an empty string if it was given as an ID or a url.
+func (o *GameOptions) GameQuery() string {
+	value := strings.TrimSpace(string(o.GameIDStr))
+	if _, isID, _ := parseID(value); isID {
+		return ""
+	}
+	if _, _, isURL := parseGameURL(value); isURL {
+		return ""
 	}
-	ui.Success("Upload complete :D\n")
+	return value
 }
 
-// Options is the command line options struct
-type Options struct {
-	Token          string `short:"t" long:"token" value-name:"TOKEN" description:"Your service API authentication token"`
-	GameID         int
-	GameIDStr      string `short:"g" long:"game" value-name:"GAME-ID" description:"The game ID"`
-	PackageID      int
-	PackageIDStr   string `short:"p" long:"package" value-name:"PACKAGE" description:"The package ID"`
-	ReleaseVersion string `short:"r" long:"release" value-name:"VERSION" description:"The release version to attach the build file to[1]"`
-	IsBrowser      bool   `short:"b" long:"browser" description:"Upload a browser build. By default uploads a desktop build."`
-	Advanced       struct {
-		ChunkSize int  `long:"chunk-size" value-name:"MB" description:"How big should the chunks the CLI uploads be. Defaults to 10."`
-		NoResume  bool `long:"no-resume" description:"Do not resume an existing upload. Start over if an upload already exists."`
-	} `group:"Advanced Options"`
-	Help    bool `short:"h" long:"help" description:"Show this help message"`
-	Version bool `short:"v" long:"version" description:"Display the version"`
-	Args    struct {
-		File string `positional-arg-name:"FILE" description:"The file to upload"`
-	} `positional-args:"1" required:"1"`
+// PackageOptions are the options that select the game package a command works with
+type PackageOptions struct {
+	GameOptions
+	PackageIDStr PackageIDValue `short:"p" long:"package" value-name:"PACKAGE" description:"The package ID or url"`
+}
+
+// IDs validates and returns the game and package IDs. IDs that were not given are returned as 0.
+// Urls given for either option can hold both IDs.
+func (o *PackageOptions) IDs() (gameID, packageID int, err error) {
+	gameID, err = o.GameID()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// A dashboard url given for the game can also point to the package
+	_, packageID, _ = parseGameURL(strings.TrimSpace(string(o.GameIDStr)))
+
+	value := strings.TrimSpace(string(o.PackageIDStr))
+	if value == "" {
+		return gameID, packageID, nil
+	}
+
+	if id, isID, err := parseID(value); isID {
+		if err != nil {
+			return 0, 0, withExitCode(ExitUsage, fmt.Errorf("Oh no, invalid package ID - %s", err.Error()))
+		}
+		return gameID, id, nil
+	}
+
+	urlGameID, urlPackageID, isURL := parseGameURL(value)
+	if !isURL {
+		return 0, 0, withExitCode(ExitUsage, errors.New("Oh no, invalid package - expected a package ID or url"))
+	}
+	if urlPackageID == 0 {
+		return 0, 0, withExitCode(ExitUsage, errors.New("Oh no, there is no package ID in the url"))
+	}
+	if gameID == 0 && o.GameQuery() == "" {
+		gameID = urlGameID
+	}
+	return gameID, urlPackageID, nil
 }
 
 // Credentials is the structure of the credentials file used to fetch the token from if none is specified
@@ -109,35 +186,47 @@ type Credentials struct {
 	Token string `json:"token"`
 }
 
-// ParseOptions parses the command line options
-// If the program should stop after
-func ParseOptions() (*Options, error) {
-	opts := &Options{}
+// ParseOptions parses the command line options into opts, and returns the command to run along with its arguments.
+// If help or version flags were given, they are printed and no command is returned.
+func ParseOptions() (flags.Commander, []string, error) {
+	var command flags.Commander
+	var commandArgs []string
+
 	parser := flags.NewParser(opts, flags.PassDoubleDash)
 	parser.Usage += "[OPTIONS]"
+	parser.CommandHandler = func(cmd flags.Commander, args []string) error {
+		command = cmd
+		commandArgs = args
+		return nil
+	}
+	parser.CompletionHandler = printCompletions
+
+	args := os.Args[1:]
+	if os.Getenv("GO_FLAGS_COMPLETION") != "" {
+		args = prepareCompletion(parser, args)
+	} else {
+		args = withDefaultCommand(parser, args)
+	}
 
-	optStrings, err := parser.Parse()
+	optStrings, err := parser.ParseArgs(args)
 	err = findHelpOrVersionFlags(opts, optStrings, err)
 
 	if err != nil {
 		ui.Error("Oh no, %s!\n\n", err.Error())
 		opts.Help = true
+		opts.invalid = true
 	}
 
 	// If we got passed a help/version flag, we dont care if the rest of the arguments are invalid,
 	// because we'll only print the help/version - so we early out here.
 	if opts.Help {
 		PrintHelp(parser)
-		return opts, nil
+		return nil, nil, nil
 	}
 
 	if opts.Version {
 		PrintVersion()
-		return opts, nil
-	}
-
-	if len(optStrings) > 0 {
-		return nil, errors.New("Too many arguments! Maybe you need to escape the file name if it contains spaces?")
+		return nil, nil, nil
 	}
 
 	// If token is not specified, attempt getting it from an environment variable or a credentials file
@@ -145,23 +234,46 @@ func ParseOptions() (*Options, error) {
 		opts.Token = getTokenFallback()
 	}
 
-	if opts.GameIDStr != "" {
-		gameID, err := strconv.Atoi(opts.GameIDStr)
-		if err != nil || gameID < 1 {
-			return nil, errors.New("Oh no, invalid game ID - expected a positive integer")
+	return command, commandArgs, nil
+}
+
+// withDefaultCommand makes push the default command, so that 'gjpush [OPTIONS] FILE' keeps working.
+// The first argument that isn't a global option decides: if it isn't a command, push is prepended to the arguments.
+func withDefaultCommand(parser *flags.Parser, args []string) []string {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if arg == "--" || arg == "-" || !strings.HasPrefix(arg, "-") {
+			if parser.Find(arg) != nil {
+				return args
+			}
+			return append([]string{"push"}, args...)
 		}
-		opts.GameID = gameID
-	}
 
-	if opts.PackageIDStr != "" {
-		packageID, err := strconv.Atoi(opts.PackageIDStr)
-		if err != nil || packageID < 1 {
-			return nil, errors.New("Oh no, invalid package ID - expected a positive integer")
+		var option *flags.Option
+		hasValue := false
+		if strings.HasPrefix(arg, "--") {
+			parts := strings.SplitN(arg[2:], "=", 2)
+			option = parser.FindOptionByLongName(parts[0])
+			hasValue = len(parts) == 2
+		} else {
+			option = parser.FindOptionByShortName(rune(arg[1]))
+			hasValue = len(arg) > 2
+		}
+
+		// Options that aren't global must be push options
+		if option == nil {
+			return append([]string{"push"}, args...)
+		}
+
+		// Skip over the option's value
+		if !hasValue && option.Field().Type.Kind() != reflect.Bool {
+			i++
 		}
-		opts.PackageID = packageID
 	}
 
-	return opts, nil
+	// Only global options were given, let the parser complain about the missing command
+	return args
 }
 
 func findHelpOrVersionFlags(opts *Options, optStrings []string, err error) error {
@@ -190,152 +302,112 @@ func PrintVersion() {
 	fmt.Printf("%s %s\n", project.Name, project.Version)
 }
 
-// PrintHelp prints the help
+// PrintHelp prints the help for the command that was given, or the global help if no command was given
 func PrintHelp(parser *flags.Parser) {
 	parser.WriteHelp(os.Stdout)
-	fmt.Println("\n" +
-		"Notes:\n" +
-		"  [1] Semver compatible release version. If the specified game doesn't have this release yet, it will be created.")
+
+	if parser.Active != nil && parser.Active.Name == "push" {
+		fmt.Println("\n" +
+			"Notes:\n" +
+			"  [1] Semver compatible release version. If the specified game doesn't have this release yet, it will be created.")
+	}
 }
 
-// ErrorAndExit prints an string with error formatting and exits with code 1
-func ErrorAndExit(str string, a ...interface{}) {
-	ui.Error(str, a...)
-	Exit(1)
+// ErrorAndExit prints an error with error formatting and exits with the exit code matching its failure class
+func ErrorAndExit(err error) {
+	ui.Error("%s\n", err.Error())
+	Exit(ExitCode(err))
+}
+
+// handleInterrupt makes sure the program exits with the interrupted exit code when the user interrupts it
+func handleInterrupt() {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	go func() {
+		<-interrupt
+		ui.Error("\nInterrupted\n")
+		Exit(ExitInterrupted)
+	}()
 }
 
 // Exit exits the program
 func Exit(code int) {
+	closeTrace()
 	color.Unset()
 	os.Exit(code)
 }
 
-func getTokenFallback() (token string) {
+func getTokenFallback() string {
+	token, source, err := readTokenFallback()
+	if err != nil {
+		ui.Warn("%s\n", err.Error())
+	}
+	if token != "" {
+		ui.Info("Using token from %s\n", source)
+	}
+	return token
+}
+
+// readTokenFallback reads the token from the GJPUSH_TOKEN environment variable or the credentials file without printing anything.
+// Returns the token along with where it was found.
+func readTokenFallback() (token, source string, err error) {
 	// Attempt to get the token from the GJPUSH_TOKEN environment variable
 	token = os.Getenv("GJPUSH_TOKEN")
 	if token != "" {
-		ui.Info("Using token from `GJPUSH_TOKEN` environment variable\n")
-		return
+		return token, "`GJPUSH_TOKEN` environment variable", nil
 	}
 
 	// Attempt to get the token from the ~/.gj/credentials.json file
-	if dir, err := os.UserHomeDir(); err == nil {
-		credentialsFile := filepath.Join(dir, ".gj", "credentials.json")
+	if credentialsFile, err := gjPath("credentials.json"); err == nil {
 		if bytes, err := ioutil.ReadFile(credentialsFile); err == nil {
 			creds := &Credentials{}
 			if err = json.Unmarshal(bytes, creds); err == nil {
-				ui.Info("Using token from credentials file\n")
-				token = creds.Token
-				return
+				return creds.Token, "credentials file", nil
 			}
 
-			ui.Warn("Attempted to get token credentials file (%s), but the file is malformed: %s\n", credentialsFile, err.Error())
+			return "", "", fmt.Errorf("Attempted to get token credentials file (%s), but the file is malformed: %s", credentialsFile, err.Error())
 		}
 	}
 
-	return
+	return "", "", nil
 }
 
-func getFileData(path string) (int64, string, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			err = errors.New("File doesn't exist")
-		} else if os.IsPermission(err) {
-			err = errors.New("No permission to read the file")
-		}
-		return 0, "", err
-	}
-	file.Close()
+// trace is where http requests are logged to, or nil if they aren't.
+// It is opened once and shared by every api client, so that a trace file holds every request of the run.
+var trace io.Writer
 
-	filesize, err := fs.Filesize(path)
-	if err != nil {
-		return 0, "", errors.New("Failed to determine filesize for some reason")
+// openTrace opens where http requests should be logged to, or returns nil if they shouldn't be logged
+func openTrace(opts *Options) (io.Writer, error) {
+	if opts.Advanced.Trace != "" {
+		file, err := os.Create(opts.Advanced.Trace)
+		if err != nil {
+			return nil, errors.New("Failed to create the trace file: " + err.Error())
+		}
+		ui.Info("Logging requests to %s\n", opts.Advanced.Trace)
+		return file, nil
 	}
 
-	checksum, err := md5File(path, filesize)
-	if err != nil {
-		return 0, "", errors.New("Failed to calculate checksum for the file.\nHas it changed while I was running?")
+	if opts.Advanced.Verbose {
+		return os.Stderr, nil
 	}
 
-	return filesize, checksum, nil
+	return nil, nil
 }
 
-func md5File(path string, filesize int64) (string, error) {
-	hash := md5.New()
-
-	file, err := os.Open(path)
-	if err != nil {
-		return "", err
-	}
-	defer file.Close()
-
-	isSlow := false
-	_, err = _io.CopyWithSlowBar(hash, file, 2*time.Second, func() *pb.ProgressBar {
-		isSlow = true
-		ui.Info("Calculating checksum...\n")
-		return pb.New64(filesize).SetMaxWidth(80).Set(pb.Bytes, true).Start()
-	})
-
-	if isSlow {
-		ui.Info("\n")
-	}
-
-	if err != nil {
-		return "", err
+// closeTrace closes the trace file, if requests are logged to one
+func closeTrace() {
+	if file, ok := trace.(*os.File); ok && file != os.Stderr {
+		file.Close()
 	}
-
-	var result []byte
-	result = hash.Sum(result)
-	return hex.EncodeToString(result), nil
-}
-
-// GetParams gets the parsed parameters, prompts for missing ones, validates, and returns them if they are valid
-func GetParams(opts *Options) (*api.Client, *models.Game, *models.GamePackage, *semver.Version, string, int64, string, *files.GetResult, int64, error) {
-	path := opts.Args.File
-	filesize, checksum, err := getFileData(path)
-	if err != nil {
-		return nil, nil, nil, nil, "", 0, "", nil, 0, err
-	}
-
-	apiClient, user, err := Authenticate(opts.Token)
-	if err != nil {
-		return nil, nil, nil, nil, "", 0, "", nil, 0, err
-	}
-
-	ui.Success("Hello, %s\n\n", user.Username)
-	game, err := GetGame(apiClient, opts.GameID)
-	if err != nil {
-		return nil, nil, nil, nil, "", 0, "", nil, 0, err
-	}
-
-	gamePackage, err := GetGamePackage(apiClient, game.ID, opts.PackageID)
-	if err != nil {
-		return nil, nil, nil, nil, "", 0, "", nil, 0, err
-	}
-
-	releaseSemver, err := GetGameRelease(apiClient, opts.ReleaseVersion)
-	if err != nil {
-		return nil, nil, nil, nil, "", 0, "", nil, 0, err
-	}
-
-	fileStatus, err := apiClient.FileStatus(game.ID, filesize, checksum)
-	if err != nil {
-		return nil, nil, nil, nil, "", 0, "", nil, 0, err
-	}
-
-	chunkSize := int64(opts.Advanced.ChunkSize) * 1024 * 1024
-	if chunkSize <= 0 {
-		chunkSize = config.ChunkSize
-	}
-
-	return apiClient, game, gamePackage, releaseSemver, path, filesize, checksum, fileStatus, chunkSize, nil
 }
 
 // Authenticate uses the given token to authenticate the user.
 // On a successful authentication, an API client will be returned for use in the rest of the lifetime of the program.
 // If auth token is not given, it will be prompted.
-func Authenticate(token string) (*api.Client, *models.User, error) {
+func Authenticate(opts *Options) (*api.Client, *models.User, error) {
+	token := opts.Token
+
 	// Prompt for the auth token if not given
 	if token == "" {
 		ui.Prompt("Enter your authentication token: ")
@@ -349,8 +421,12 @@ func Authenticate(token string) (*api.Client, *models.User, error) {
 		token = strings.TrimSpace(string(tokenBytes))
 	}
 
+	apiClient, err := NewAPIClient(opts, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Validate it
-	apiClient := api.NewClient(token)
 	user, err := apiClient.Me()
 	if err != nil {
 		return nil, nil, err
@@ -359,8 +435,44 @@ func Authenticate(token string) (*api.Client, *models.User, error) {
 	return apiClient, user, nil
 }
 
+// NewAPIClient creates an API client for the given token, configured with the network options
+func NewAPIClient(opts *Options, token string) (*api.Client, error) {
+	settings := loadSettings()
+
+	transportOpts, err := GetTransportOptions(opts, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := cliHttp.NewClient(transportOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	maxRateLimitWait, err := GetMaxRateLimitWait(opts, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	apiClient := api.NewClient(token)
+	apiClient.SetHTTPClient(httpClient)
+	apiClient.SetMaxRateLimitWait(maxRateLimitWait)
+	if trace != nil {
+		apiClient.SetTrace(trace)
+	}
+
+	return apiClient, nil
+}
+
 // GetGame gets and validates a game by a given id. If the id is not given, it will be prompted.
 func GetGame(apiClient *api.Client, gameID int) (*models.Game, error) {
+	if gameID == 0 && ui.CanPick() {
+		var err error
+		if gameID, err = PickGame(apiClient); err != nil {
+			return nil, err
+		}
+	}
+
 	if gameID == 0 {
 		ui.Prompt("Enter a game ID: ")
 		gameIDStr, err := inReader.ReadString('\n')
@@ -369,7 +481,7 @@ func GetGame(apiClient *api.Client, gameID int) (*models.Game, error) {
 		}
 		gameID, err = strconv.Atoi(strings.TrimSpace(gameIDStr))
 		if err != nil || gameID < 1 {
-			return nil, errors.New("Invalid game ID - expected a positive integer")
+			return nil, withExitCode(ExitUsage, errors.New("Invalid game ID - expected a positive integer"))
 		}
 	}
 
@@ -384,7 +496,14 @@ func GetGame(apiClient *api.Client, gameID int) (*models.Game, error) {
 // GetGamePackage gets and validates a game package by a given id. If the id is not given, it will be prompted.
 func GetGamePackage(apiClient *api.Client, gameID, packageID int) (*models.GamePackage, error) {
 	if gameID == 0 {
-		return nil, errors.New("Game ID must be provided")
+		return nil, withExitCode(ExitUsage, errors.New("Game ID must be provided"))
+	}
+
+	if packageID == 0 && ui.CanPick() {
+		var err error
+		if packageID, err = PickGamePackage(apiClient, gameID); err != nil {
+			return nil, err
+		}
 	}
 
 	if packageID == 0 {
@@ -395,7 +514,7 @@ func GetGamePackage(apiClient *api.Client, gameID, packageID int) (*models.GameP
 		}
 		packageID, err = strconv.Atoi(strings.TrimSpace(packageIDStr))
 		if err != nil || packageID < 1 {
-			return nil, errors.New("Invalid package ID - expected a positive integer")
+			return nil, withExitCode(ExitUsage, errors.New("Invalid package ID - expected a positive integer"))
 		}
 	}
 
@@ -407,6 +526,102 @@ func GetGamePackage(apiClient *api.Client, gameID, packageID int) (*models.GameP
 	return gamePackage, nil
 }
 
+// PickGame lets the user pick one of their games from a list. Returns 0 if there are no games to pick from.
+func PickGame(apiClient *api.Client) (int, error) {
+	games, err := ListAllGames(apiClient)
+	if err != nil {
+		return 0, err
+	}
+	if len(games) == 0 {
+		return 0, nil
+	}
+
+	items := make([]string, len(games))
+	for i, game := range games {
+		items[i] = fmt.Sprintf("%s (ID: %d)", game.Title, game.ID)
+	}
+
+	picked, err := ui.Pick(inReader, "Pick a game:", items)
+	if err != nil {
+		return 0, pickError(err)
+	}
+	return games[picked].ID, nil
+}
+
+// PickGamePackage lets the user pick one of the packages of a game from a list. Returns 0 if there are no packages to pick from.
+func PickGamePackage(apiClient *api.Client, gameID int) (int, error) {
+	gamePackages, err := ListAllPackages(apiClient, gameID)
+	if err != nil {
+		return 0, err
+	}
+	if len(gamePackages) == 0 {
+		return 0, nil
+	}
+
+	items := make([]string, len(gamePackages))
+	for i, gamePackage := range gamePackages {
+		items[i] = fmt.Sprintf("%s (ID: %d)", gamePackage.Title, gamePackage.ID)
+	}
+
+	picked, err := ui.Pick(inReader, "Pick a package:", items)
+	if err != nil {
+		return 0, pickError(err)
+	}
+	return gamePackages[picked].ID, nil
+}
+
+func pickError(err error) error {
+	if errors.Is(err, ui.ErrPickCanceled) {
+		return withExitCode(ExitInterrupted, errors.New("Interrupted"))
+	}
+	return err
+}
+
+// GetGameParams authenticates and gets the game the given options select, prompting for it if missing
+func GetGameParams(gameOpts *GameOptions) (*api.Client, *models.Game, error) {
+	gameID, err := gameOpts.GameID()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return getGameParams(gameOpts, gameID)
+}
+
+func getGameParams(gameOpts *GameOptions, gameID int) (*api.Client, *models.Game, error) {
+	apiClient, user, err := Authenticate(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ui.Success("Hello, %s\n\n", user.Username)
+	game, err := LookupGame(apiClient, gameOpts, gameID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return apiClient, game, nil
+}
+
+// GetPackageParams authenticates and gets the game package the given options select, prompting for missing ones
+func GetPackageParams(packageOpts *PackageOptions) (*api.Client, *models.Game, *models.GamePackage, error) {
+	gameID, packageID, err := packageOpts.IDs()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	apiClient, game, err := getGameParams(&packageOpts.GameOptions, gameID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	gamePackage, err := GetGamePackage(apiClient, game.ID, packageID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return apiClient, game, gamePackage, nil
+}
+
 // GetGameRelease gets and validates a game release by a given release version.
 // If the release version is not given, it will be prompted.
 func GetGameRelease(apiClient *api.Client, releaseVersion string) (*semver.Version, error) {
@@ -422,53 +637,27 @@ func GetGameRelease(apiClient *api.Client, releaseVersion string) (*semver.Versi
 
 	semver, err := semver.Make(releaseVersion)
 	if err != nil {
-		return nil, errors.New("Invalid semver. Check out https://semver.org")
+		return nil, withExitCode(ExitUsage, errors.New("Invalid semver. Check out https://semver.org"))
 	}
 	return &semver, nil
 }
 
-// Upload uploads a file to a game
-func Upload(apiClient *api.Client, game *models.Game, gamePackage *models.GamePackage, releaseSemver *semver.Version, browserBuild bool, filepath string, filesize int64, checksum string, startByte, chunkSize int64) error {
-	// Create a new progress bar that starts from the given start byte
-	bar := pb.New64(filesize).SetMaxWidth(80).Set(pb.Bytes, true).SetTemplateString("")
-	bar.Add64(startByte)
-
-	// The bar will be set to visible by the apiClient as soon as it knows it wouldn't print any errors right off the bat
-	bar.Start()
-	defer bar.Finish()
-
-	for {
-		result, err := uploadChunk(apiClient, game, gamePackage, releaseSemver, browserBuild, filepath, filesize, checksum, startByte, chunkSize, bar)
-		if err != nil {
-			return err
-		}
-
-		if result.Status == "complete" {
-			return nil
-		}
-
-		// Get next chunk
-		startByte = result.Start
-	}
-}
-
-func uploadChunk(apiClient *api.Client, game *models.Game, gamePackage *models.GamePackage, releaseSemver *semver.Version, browserBuild bool, filepath string, filesize int64, checksum string, startByte, chunkSize int64, bar *pb.ProgressBar) (*files.AddResult, error) {
-	result, err := apiClient.FileAdd(game.ID, gamePackage.ID, releaseSemver, !browserBuild, filesize, checksum, false, filepath, startByte, chunkSize, bar)
+// Confirm prompts the user with a yes/no question. Defaults to no.
+func Confirm(question string) (bool, error) {
+	ui.Prompt("%s [y/N]: ", question)
+	answer, err := inReader.ReadString('\n')
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 
-	if result.Status == "complete" {
-		return result, nil
-	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
 
-	if result.Status == "error" || result.Start <= startByte {
-		return nil, errors.New(`Uh oh, something went wrong!
-This could happen for a couple of reasons:
-    • The file changed while uploading
-    • File upload has expired (no progress in the last day or so)
-    • We fucked up`)
+// formatTimestamp formats a millisecond timestamp returned by the api
+func formatTimestamp(timestamp int64) string {
+	if timestamp == 0 {
+		return "-"
 	}
-
-	return result, nil
+	return time.Unix(0, timestamp*int64(time.Millisecond)).Format("2006-01-02 15:04")
 }
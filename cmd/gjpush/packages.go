@@ -0,0 +1,136 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/gamejolt/cli/pkg/api"
+	"github.com/gamejolt/cli/pkg/api/models"
+	"github.com/gamejolt/cli/pkg/api/packages"
+	"github.com/gamejolt/cli/pkg/ui"
+)
+
+// PackagesCommand is the packages command, which groups the package management commands
+type PackagesCommand struct {
+	List   PackagesListCommand   `command:"list" description:"List the packages of a game"`
+	Create PackagesCreateCommand `command:"create" description:"Create a new package"`
+	Update PackagesUpdateCommand `command:"update" description:"Update an existing package"`
+}
+
+// PackageFieldOptions are the options for the fields of a package to create or update
+type PackageFieldOptions struct {
+	Title       *string `long:"title" value-name:"TITLE" description:"The package title"`
+	Description *string `long:"description" value-name:"TEXT" description:"The package description"`
+	Visibility  *string `long:"visibility" value-name:"VISIBILITY" choice:"public" choice:"private" description:"Who can see the package"`
+	Sort        *int    `long:"sort" value-name:"N" description:"The position of the package on the game page"`
+}
+
+// SaveOptions returns the package fields to send to the api
+func (o *PackageFieldOptions) SaveOptions() *packages.SaveOptions {
+	return &packages.SaveOptions{
+		Title:       o.Title,
+		Description: o.Description,
+		Visibility:  o.Visibility,
+		Sort:        o.Sort,
+	}
+}
+
+// PackagesListCommand is the packages list command
+type PackagesListCommand struct {
+	GameOptions
+}
+
+// Execute runs the packages list command
+func (c *PackagesListCommand) Execute(args []string) error {
+	apiClient, game, err := GetGameParams(&c.GameOptions)
+	if err != nil {
+		return err
+	}
+
+	gamePackages, err := ListAllPackages(apiClient, game.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(gamePackages) == 0 {
+		ui.Info("%s has no packages yet\n", game.Title)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE\tVISIBILITY\tSTATUS\tSORT")
+	for _, gamePackage := range gamePackages {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\n", gamePackage.ID, gamePackage.Title, gamePackage.Visibility, gamePackage.Status, gamePackage.Sort)
+	}
+	return w.Flush()
+}
+
+// PackagesCreateCommand is the packages create command
+type PackagesCreateCommand struct {
+	GameOptions
+	PackageFieldOptions
+}
+
+// Execute runs the packages create command
+func (c *PackagesCreateCommand) Execute(args []string) error {
+	if c.Title == nil || *c.Title == "" {
+		return withExitCode(ExitUsage, errors.New("A package title must be given with --title"))
+	}
+
+	apiClient, game, err := GetGameParams(&c.GameOptions)
+	if err != nil {
+		return err
+	}
+
+	gamePackage, err := apiClient.GamePackageCreate(game.ID, c.SaveOptions())
+	if err != nil {
+		return err
+	}
+
+	ui.Success("Created package %s (ID: %d)\n", gamePackage.Title, gamePackage.ID)
+	return nil
+}
+
+// PackagesUpdateCommand is the packages update command
+type PackagesUpdateCommand struct {
+	PackageOptions
+	PackageFieldOptions
+}
+
+// Execute runs the packages update command
+func (c *PackagesUpdateCommand) Execute(args []string) error {
+	if c.Title == nil && c.Description == nil && c.Visibility == nil && c.Sort == nil {
+		return withExitCode(ExitUsage, errors.New("Nothing to update, pass at least one of --title, --description, --visibility or --sort"))
+	}
+
+	apiClient, game, gamePackage, err := GetPackageParams(&c.PackageOptions)
+	if err != nil {
+		return err
+	}
+
+	gamePackage, err = apiClient.GamePackageUpdate(game.ID, gamePackage.ID, c.SaveOptions())
+	if err != nil {
+		return err
+	}
+
+	ui.Success("Updated package %s (ID: %d)\n", gamePackage.Title, gamePackage.ID)
+	return nil
+}
+
+// ListAllPackages gets all the packages of a game, going through all the pages
+func ListAllPackages(apiClient *api.Client, gameID int) ([]models.GamePackage, error) {
+	result := []models.GamePackage{}
+	for page := 1; ; page++ {
+		gamePackages, err := apiClient.GamePackages(&packages.ListOptions{GameID: gameID, Page: page})
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, gamePackages.Packages...)
+		if len(gamePackages.Packages) == 0 || gamePackages.PerPage <= 0 || len(result) >= gamePackages.Total {
+			return result, nil
+		}
+	}
+}
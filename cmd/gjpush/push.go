@@ -0,0 +1,505 @@
+package main
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gamejolt/cli/config"
+	"github.com/gamejolt/cli/pkg/api"
+	"github.com/gamejolt/cli/pkg/api/builds"
+	apiErrors "github.com/gamejolt/cli/pkg/api/errors"
+	"github.com/gamejolt/cli/pkg/api/files"
+	"github.com/gamejolt/cli/pkg/api/models"
+	"github.com/gamejolt/cli/pkg/api/releases"
+	"github.com/gamejolt/cli/pkg/fs"
+	"github.com/gamejolt/cli/pkg/inspect"
+	_io "github.com/gamejolt/cli/pkg/io"
+	"github.com/gamejolt/cli/pkg/ui"
+
+	semver "github.com/blang/semver/v4"
+	pb "github.com/cheggaaa/pb/v3"
+)
+
+// maxChunkAttempts is how many times a single chunk will be uploaded before giving up on it.
+const maxChunkAttempts = 3
+
+// ReleaseOptions are the options that pick the release version a build is pushed to
+type ReleaseOptions struct {
+	ReleaseVersion string `short:"r" long:"release" value-name:"VERSION" description:"The release version to attach the build file to[1]"`
+	ReleaseFromGit bool   `long:"release-from-git" description:"Take the release version from the latest git tag of the current directory. Commits made after the tag are added as build metadata."`
+	Bump           string `long:"bump" choice:"major" choice:"minor" choice:"patch" choice:"prerelease" description:"Attach the build file to a new release, bumping the highest existing release version"`
+}
+
+// PushCommand is the push command, which uploads a build file
+type PushCommand struct {
+	PackageOptions
+	ReleaseOptions
+	NotesOptions
+	IsBrowser    bool `short:"b" long:"browser" description:"Upload a browser build. By default uploads a desktop build."`
+	DryRun       bool `long:"dry-run" description:"Validate everything and report what would be uploaded without uploading anything."`
+	Replace      bool `long:"replace" description:"Replace the builds in the release that have the same filename or are for the same platforms as the uploaded build."`
+	Force        bool `long:"force" description:"Push the build even if checking it found issues."`
+	SetPlatforms bool `long:"set-platforms" description:"Set the platforms and launch options of the build to the ones detected from the executables in the file."`
+	EmbedWidth   *int `long:"embed-width" value-name:"PX" description:"The width to embed the browser build at. Detected from the build by default."`
+	EmbedHeight  *int `long:"embed-height" value-name:"PX" description:"The height to embed the browser build at. Detected from the build by default."`
+	Unity        bool `long:"unity" description:"Push a Unity build folder, like Builds/StandaloneWindows64. The platform, architecture and main executable are taken from the build, and the folder is zipped."`
+	Advanced     struct {
+		ChunkSize int  `long:"chunk-size" value-name:"MB" description:"How big should the chunks the CLI uploads be. Defaults to 10."`
+		NoResume  bool `long:"no-resume" description:"Do not resume an existing upload. Start over if an upload already exists."`
+	} `group:"Advanced Options"`
+	Args struct {
+		File string `positional-arg-name:"FILE" description:"The file to upload"`
+	} `positional-args:"1" required:"1"`
+}
+
+// Execute runs the push command
+func (c *PushCommand) Execute(args []string) error {
+	if len(args) > 0 {
+		return withExitCode(ExitUsage, errors.New("Too many arguments! Maybe you need to escape the file name if it contains spaces?"))
+	}
+	if err := c.ValidateRelease(); err != nil {
+		return err
+	}
+
+	var unityUpdate *builds.UpdateOptions
+	if c.Unity {
+		update, cleanup, err := c.packUnityBuild()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		unityUpdate = update
+	}
+
+	if !c.IsBrowser && (c.EmbedWidth != nil || c.EmbedHeight != nil) {
+		return withExitCode(ExitUsage, errors.New("The --embed-width and --embed-height options can only be used for browser builds"))
+	}
+	if c.EmbedWidth != nil && *c.EmbedWidth < 1 || c.EmbedHeight != nil && *c.EmbedHeight < 1 {
+		return withExitCode(ExitUsage, errors.New("The embed size has to be a positive number of pixels"))
+	}
+
+	apiClient, game, gamePackage, releaseSemver, filepath, filesize, checksum, fileStatus, chunkSize, err := c.GetParams()
+	if err != nil {
+		return err
+	}
+
+	notes, err := c.ReadNotes(releaseSemver)
+	if err != nil {
+		return err
+	}
+
+	// Build settings to set once the build is uploaded
+	var buildUpdate *builds.UpdateOptions
+
+	if c.IsBrowser {
+		if err = CheckBrowserBuild(filepath, c.Force); err != nil {
+			return err
+		}
+		buildUpdate = DetectEmbedSize(filepath, c.EmbedWidth, c.EmbedHeight)
+	} else if unityUpdate != nil {
+		buildUpdate = unityUpdate
+	} else if detected := DetectPlatforms(filepath); detected != nil {
+		if c.SetPlatforms {
+			buildUpdate = detected
+		} else {
+			ui.Info("Pass --set-platforms to set them on the build.\n")
+		}
+		ui.Info("\n")
+	}
+
+	if c.DryRun {
+		if notes != nil {
+			ui.Info("Release notes from %s:\n%s\n\n", c.Notes, *notes)
+		}
+		return c.ReportDryRun(apiClient, game, gamePackage, releaseSemver, filepath, filesize, checksum, fileStatus)
+	}
+
+	build, err := StartUpload(apiClient, game, gamePackage, releaseSemver, c.IsBrowser, filepath, filesize, checksum, fileStatus, chunkSize, c.Advanced.NoResume)
+	if err != nil {
+		return err
+	}
+	ui.Success("Upload complete :D\n")
+
+	if buildUpdate != nil && build != nil {
+		if build, err = UpdateBuild(apiClient, game, gamePackage, build, buildUpdate); err != nil {
+			return err
+		}
+	}
+
+	if c.Replace {
+		if err = c.replaceBuilds(apiClient, game, gamePackage, releaseSemver, build, filepath); err != nil {
+			return err
+		}
+	}
+
+	if notes != nil {
+		return attachNotes(apiClient, game, gamePackage, releaseSemver, notes)
+	}
+	return nil
+}
+
+// CheckBrowserBuild checks a zipped browser build for issues before uploading it. Warnings are reported,
+// and the push is refused if any other issues are found unless forced to.
+func CheckBrowserBuild(path string, force bool) error {
+	if !strings.EqualFold(filepath.Ext(path), ".zip") {
+		return nil
+	}
+
+	issues, err := inspect.CheckHTML5(path)
+	if err != nil {
+		return withExitCode(ExitInvalidBuild, err)
+	}
+	warnings, blocking := []inspect.Issue{}, []inspect.Issue{}
+	for _, issue := range issues {
+		if issue.Warning {
+			warnings = append(warnings, issue)
+		} else {
+			blocking = append(blocking, issue)
+		}
+	}
+
+	// Engine settings may work depending on how the build is served, so they don't stop the push
+	if len(warnings) > 0 {
+		ui.Warn("The browser build may not load on Game Jolt:\n")
+		for _, issue := range warnings {
+			ui.Warn("  - %s\n", issue.String())
+		}
+		ui.Warn("\n")
+	}
+	if len(blocking) == 0 {
+		return nil
+	}
+
+	ui.Warn("Found issues with the browser build:\n")
+	for _, issue := range blocking {
+		ui.Warn("  - %s\n", issue.String())
+	}
+	ui.Warn("\n")
+
+	if force {
+		ui.Warn("Pushing it anyway because of --force\n\n")
+		return nil
+	}
+	return withExitCode(ExitInvalidBuild, errors.New("Refusing to push the browser build. Fix the issues above, or push it anyway with --force"))
+}
+
+// attachNotes sets the release notes of the release the build was pushed to
+func attachNotes(apiClient *api.Client, game *models.Game, gamePackage *models.GamePackage, releaseSemver *semver.Version, notes *string) error {
+	release, err := FindRelease(apiClient, game.ID, gamePackage.ID, releaseSemver)
+	if err != nil {
+		return err
+	}
+
+	ui.Info("Attaching the release notes to release %s ...\n", release.Version)
+	_, err = apiClient.ReleaseUpdate(game.ID, gamePackage.ID, release.ID, &releases.UpdateOptions{Notes: notes})
+	return err
+}
+
+// replaceBuilds removes the builds the newly uploaded build replaces from the release
+func (c *PushCommand) replaceBuilds(apiClient *api.Client, game *models.Game, gamePackage *models.GamePackage, releaseSemver *semver.Version, build *models.GameBuild, filepath string) error {
+	// Without the new build, it can't be told apart from the builds it replaces, and would be removed along with them
+	if build == nil {
+		return withExitCode(ExitUnexpectedResponse, errors.New("The upload is done, but the server didn't say which build it made, so no builds were replaced. Remove the old builds with 'builds delete'"))
+	}
+
+	replaced, err := ReplacedBuilds(apiClient, game, gamePackage, releaseSemver, build, filepath)
+	if err != nil {
+		return err
+	}
+
+	for _, oldBuild := range replaced {
+		ui.Info("Removing the replaced build (Build ID: %d) ...\n", oldBuild.ID)
+		if err = apiClient.BuildDelete(game.ID, gamePackage.ID, oldBuild.ID); err != nil {
+			return err
+		}
+	}
+
+	if len(replaced) == 0 {
+		ui.Info("There were no builds to replace in release %s\n", releaseSemver.String())
+	}
+	return nil
+}
+
+// GetParams gets the parsed parameters, prompts for missing ones, validates, and returns them if they are valid
+func (c *PushCommand) GetParams() (*api.Client, *models.Game, *models.GamePackage, *semver.Version, string, int64, string, *files.GetResult, int64, error) {
+	gameID, packageID, err := c.IDs()
+	if err != nil {
+		return nil, nil, nil, nil, "", 0, "", nil, 0, err
+	}
+
+	path := c.Args.File
+	filesize, checksum, err := getFileData(path)
+	if err != nil {
+		return nil, nil, nil, nil, "", 0, "", nil, 0, err
+	}
+
+	apiClient, user, err := Authenticate(opts)
+	if err != nil {
+		return nil, nil, nil, nil, "", 0, "", nil, 0, err
+	}
+
+	ui.Success("Hello, %s\n\n", user.Username)
+	game, err := LookupGame(apiClient, &c.GameOptions, gameID)
+	if err != nil {
+		return nil, nil, nil, nil, "", 0, "", nil, 0, err
+	}
+
+	gamePackage, err := GetGamePackage(apiClient, game.ID, packageID)
+	if err != nil {
+		return nil, nil, nil, nil, "", 0, "", nil, 0, err
+	}
+
+	releaseSemver, err := c.GetReleaseVersion(apiClient, game, gamePackage)
+	if err != nil {
+		return nil, nil, nil, nil, "", 0, "", nil, 0, err
+	}
+
+	fileStatus, err := apiClient.FileStatus(game.ID, filesize, checksum)
+	if err != nil {
+		return nil, nil, nil, nil, "", 0, "", nil, 0, err
+	}
+
+	chunkSize := int64(c.Advanced.ChunkSize) * 1024 * 1024
+	if chunkSize <= 0 {
+		chunkSize = config.ChunkSize
+	}
+
+	return apiClient, game, gamePackage, releaseSemver, path, filesize, checksum, fileStatus, chunkSize, nil
+}
+
+// ValidateRelease checks that the release version is picked by one option at most
+func (o *ReleaseOptions) ValidateRelease() error {
+	if countTrue(o.ReleaseVersion != "", o.ReleaseFromGit, o.Bump != "") > 1 {
+		return withExitCode(ExitUsage, errors.New("Only one of the --release, --release-from-git and --bump options can be used"))
+	}
+	return nil
+}
+
+// GetReleaseVersion gets the release version to push to, bumping the latest release if asked to.
+// If no release version was given, it will be prompted.
+func (o *ReleaseOptions) GetReleaseVersion(apiClient *api.Client, game *models.Game, gamePackage *models.GamePackage) (*semver.Version, error) {
+	if o.ReleaseFromGit {
+		version, err := GitRelease()
+		if err != nil {
+			return nil, err
+		}
+
+		ui.Info("Using release %s from git\n", version.String())
+		return version, nil
+	}
+	if o.Bump == "" {
+		return GetGameRelease(apiClient, o.ReleaseVersion)
+	}
+
+	latest, err := LatestRelease(apiClient, game.ID, gamePackage.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := BumpVersion(*latest, o.Bump)
+	if err != nil {
+		return nil, withExitCode(ExitUsage, err)
+	}
+
+	ui.Info("Bumping release %s to %s\n", latest.String(), next.String())
+	return &next, nil
+}
+
+func countTrue(values ...bool) int {
+	count := 0
+	for _, value := range values {
+		if value {
+			count++
+		}
+	}
+	return count
+}
+
+func getFileData(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = errors.New("File doesn't exist")
+		} else if os.IsPermission(err) {
+			err = errors.New("No permission to read the file")
+		}
+		return 0, "", withExitCode(ExitFileNotFound, err)
+	}
+	file.Close()
+
+	filesize, err := fs.Filesize(path)
+	if err != nil {
+		return 0, "", withExitCode(ExitFileNotFound, errors.New("Failed to determine filesize for some reason"))
+	}
+
+	checksum, err := md5File(path, filesize)
+	if err != nil {
+		return 0, "", withExitCode(ExitChecksumFailed, errors.New("Failed to calculate checksum for the file.\nHas it changed while I was running?"))
+	}
+
+	return filesize, checksum, nil
+}
+
+func md5File(path string, filesize int64) (string, error) {
+	hash := md5.New()
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	isSlow := false
+	_, err = _io.CopyWithSlowBar(hash, file, 2*time.Second, func() *pb.ProgressBar {
+		isSlow = true
+		ui.Info("Calculating checksum...\n")
+		return pb.New64(filesize).SetMaxWidth(80).Set(pb.Bytes, true).Start()
+	})
+
+	if isSlow {
+		ui.Info("\n")
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	var result []byte
+	result = hash.Sum(result)
+	return hex.EncodeToString(result), nil
+}
+
+// ReportDryRun prints what an upload would do with the given parameters without doing it
+func (c *PushCommand) ReportDryRun(apiClient *api.Client, game *models.Game, gamePackage *models.GamePackage, releaseSemver *semver.Version, filepath string, filesize int64, checksum string, fileStatus *files.GetResult) error {
+	buildType := "desktop"
+	if c.IsBrowser {
+		buildType = "browser"
+	}
+
+	ui.Info("Dry run, nothing will be uploaded.\n\n")
+	ui.Info("File:     %s (%d bytes, checksum %s)\n", filepath, filesize, checksum)
+	ui.Info("Game:     %s (ID: %d)\n", game.Title, game.ID)
+	ui.Info("Package:  %s (ID: %d)\n", gamePackage.Title, gamePackage.ID)
+	ui.Info("Release:  %s\n", releaseSemver.String())
+	ui.Info("Build:    %s\n\n", buildType)
+
+	switch fileStatus.Status {
+	case "new":
+		ui.Success("Would start a new upload.\n")
+	case "partial":
+		if c.Advanced.NoResume {
+			ui.Success("Would abort the existing upload (File ID: %d) and start over.\n", fileStatus.FileID)
+		} else {
+			ui.Success("Would resume the existing upload (File ID: %d) at byte %d of %d.\n", fileStatus.FileID, fileStatus.Start, filesize)
+		}
+	case "error":
+		ui.Success("Would restart the existing upload (File ID: %d), the previous upload chunk had an issue.\n", fileStatus.FileID)
+	default:
+		ui.Warn("The server reported an unexpected upload status: %s\n", fileStatus.Status)
+	}
+
+	if c.Replace {
+		replaced, err := ReplacedBuilds(apiClient, game, gamePackage, releaseSemver, nil, filepath)
+		if err != nil {
+			return err
+		}
+
+		for _, build := range replaced {
+			ui.Success("Would replace the build with the same filename (Build ID: %d).\n", build.ID)
+		}
+		ui.Info("Builds for the same platforms are only known once the upload is done, they would be replaced as well.\n")
+	}
+	return nil
+}
+
+// StartUpload starts, resumes or restarts the upload of a file depending on its upload status, and uploads it
+func StartUpload(apiClient *api.Client, game *models.Game, gamePackage *models.GamePackage, releaseSemver *semver.Version, browserBuild bool, filepath string, filesize int64, checksum string, fileStatus *files.GetResult, chunkSize int64, noResume bool) (*models.GameBuild, error) {
+	if fileStatus.Status == "new" {
+		ui.Info("Starting a new upload ...\n")
+	} else if fileStatus.Status == "partial" {
+		if noResume {
+			ui.Info("Aborting existing upload (File ID: %d) ...\n", fileStatus.FileID)
+
+			_, err := apiClient.FileRestart(game.ID, filesize, checksum)
+			if err != nil {
+				return nil, err
+			}
+
+			ui.Info("Starting a new upload ...\n")
+		} else {
+			ui.Info("Resuming the upload (File ID: %d) ...\n", fileStatus.FileID)
+		}
+	} else if fileStatus.Status == "error" {
+		ui.Warn("There was an issue with the previous upload chunk, we have to start over :(\n")
+		ui.Info("Starting a new upload ...\n")
+	}
+
+	startByte := fileStatus.Start
+	if noResume || fileStatus.Status == "error" {
+		startByte = 0
+	}
+
+	return Upload(apiClient, game, gamePackage, releaseSemver, browserBuild, filepath, filesize, checksum, startByte, chunkSize)
+}
+
+// Upload uploads a file to a game
+// Returns the build the file was attached to.
+func Upload(apiClient *api.Client, game *models.Game, gamePackage *models.GamePackage, releaseSemver *semver.Version, browserBuild bool, filepath string, filesize int64, checksum string, startByte, chunkSize int64) (*models.GameBuild, error) {
+	// Create a new progress bar that starts from the given start byte
+	bar := pb.New64(filesize).SetMaxWidth(80).Set(pb.Bytes, true).SetTemplateString("")
+	bar.Add64(startByte)
+
+	// The bar will be set to visible by the apiClient as soon as it knows it wouldn't print any errors right off the bat
+	bar.Start()
+	defer bar.Finish()
+
+	attempts := 0
+	for {
+		attempts++
+		result, err := uploadChunk(apiClient, game, gamePackage, releaseSemver, browserBuild, filepath, filesize, checksum, startByte, chunkSize, bar)
+		if err != nil {
+			// A corrupted chunk can simply be sent again.
+			var apiErr *apiErrors.Error
+			if errors.As(err, &apiErr) && apiErr.Code() == apiErrors.ChunkChecksumMismatch && attempts < maxChunkAttempts {
+				ui.Warn("\nThe chunk got corrupted on the way to the server, retrying (attempt %d of %d) ...\n", attempts+1, maxChunkAttempts)
+				bar.SetCurrent(startByte)
+				continue
+			}
+			return nil, err
+		}
+		attempts = 0
+
+		if result.Status == "complete" {
+			return result.Build, nil
+		}
+
+		// Get next chunk
+		startByte = result.Start
+	}
+}
+
+func uploadChunk(apiClient *api.Client, game *models.Game, gamePackage *models.GamePackage, releaseSemver *semver.Version, browserBuild bool, filepath string, filesize int64, checksum string, startByte, chunkSize int64, bar *pb.ProgressBar) (*files.AddResult, error) {
+	result, err := apiClient.FileAdd(game.ID, gamePackage.ID, releaseSemver, !browserBuild, filesize, checksum, false, filepath, startByte, chunkSize, bar)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Status == "complete" {
+		return result, nil
+	}
+
+	if result.Status == "error" || result.Start <= startByte {
+		return nil, withExitCode(ExitUploadFailed, errors.New(`Uh oh, something went wrong!
+This could happen for a couple of reasons:
+    • The file changed while uploading
+    • File upload has expired (no progress in the last day or so)
+    • We fucked up`))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/gamejolt/cli/pkg/api"
+	"github.com/gamejolt/cli/pkg/api/models"
+	"github.com/gamejolt/cli/pkg/api/releases"
+	"github.com/gamejolt/cli/pkg/changelog"
+	"github.com/gamejolt/cli/pkg/ui"
+
+	semver "github.com/blang/semver/v4"
+)
+
+// ReleasesCommand is the releases command, which groups the release management commands
+type ReleasesCommand struct {
+	List      ReleasesListCommand      `command:"list" description:"List the releases of a game package"`
+	Create    ReleasesCreateCommand    `command:"create" description:"Create a new hidden release. Builds can be pushed to it before publishing it."`
+	Update    ReleasesUpdateCommand    `command:"update" description:"Update a release"`
+	Publish   ReleasesPublishCommand   `command:"publish" description:"Publish a release"`
+	Unpublish ReleasesUnpublishCommand `command:"unpublish" description:"Unpublish a release, hiding it again"`
+	Delete    ReleasesDeleteCommand    `command:"delete" description:"Delete a release and all of its builds"`
+}
+
+// NotesOptions are the options for attaching release notes to a release
+type NotesOptions struct {
+	Notes string `long:"notes" value-name:"FILE" description:"A markdown changelog to take the release notes from. Only the section with a heading matching the release version is used, for example '## 1.2.3'."`
+}
+
+// ReadNotes reads the release notes for the given version. Returns nil if no release notes were given.
+func (o *NotesOptions) ReadNotes(version *semver.Version) (*string, error) {
+	if o.Notes == "" {
+		return nil, nil
+	}
+
+	notes, err := changelog.ReadSection(o.Notes, *version)
+	if err != nil {
+		return nil, withExitCode(ExitUsage, err)
+	}
+	return &notes, nil
+}
+
+// ReleaseArgs are the positional arguments of the commands that work with a single release
+type ReleaseArgs struct {
+	Version string `positional-arg-name:"VERSION" description:"The semver release version"`
+}
+
+// ReleasesListCommand is the releases list command
+type ReleasesListCommand struct {
+	PackageOptions
+}
+
+// Execute runs the releases list command
+func (c *ReleasesListCommand) Execute(args []string) error {
+	apiClient, game, gamePackage, err := GetPackageParams(&c.PackageOptions)
+	if err != nil {
+		return err
+	}
+
+	gameReleases, err := ListAllReleases(apiClient, game.ID, gamePackage.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(gameReleases) == 0 {
+		ui.Info("%s has no releases yet\n", gamePackage.Title)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tSTATUS\tID\tCREATED")
+	for _, release := range gameReleases {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", release.Version, release.Status, release.ID, formatTimestamp(release.CreatedOn))
+	}
+	return w.Flush()
+}
+
+// ReleasesCreateCommand is the releases create command
+type ReleasesCreateCommand struct {
+	PackageOptions
+	NotesOptions
+	Args ReleaseArgs `positional-args:"1" required:"1"`
+}
+
+// Execute runs the releases create command
+func (c *ReleasesCreateCommand) Execute(args []string) error {
+	version, err := parseReleaseVersion(c.Args.Version)
+	if err != nil {
+		return err
+	}
+
+	notes, err := c.ReadNotes(version)
+	if err != nil {
+		return err
+	}
+
+	apiClient, game, gamePackage, err := GetPackageParams(&c.PackageOptions)
+	if err != nil {
+		return err
+	}
+
+	createOptions := &releases.CreateOptions{Version: version.String()}
+	if notes != nil {
+		createOptions.Notes = *notes
+	}
+
+	release, err := apiClient.ReleaseCreate(game.ID, gamePackage.ID, createOptions)
+	if err != nil {
+		return err
+	}
+
+	ui.Success("Created release %s (ID: %d, status: %s)\n", release.Version, release.ID, release.Status)
+	return nil
+}
+
+// ReleasesUpdateCommand is the releases update command
+type ReleasesUpdateCommand struct {
+	PackageOptions
+	NotesOptions
+	Args ReleaseArgs `positional-args:"1" required:"1"`
+}
+
+// Execute runs the releases update command
+func (c *ReleasesUpdateCommand) Execute(args []string) error {
+	version, err := parseReleaseVersion(c.Args.Version)
+	if err != nil {
+		return err
+	}
+
+	notes, err := c.ReadNotes(version)
+	if err != nil {
+		return err
+	}
+	if notes == nil {
+		return withExitCode(ExitUsage, errors.New("Nothing to update, pass the release notes with --notes"))
+	}
+
+	apiClient, game, gamePackage, release, err := GetReleaseParams(&c.PackageOptions, c.Args.Version)
+	if err != nil {
+		return err
+	}
+
+	release, err = apiClient.ReleaseUpdate(game.ID, gamePackage.ID, release.ID, &releases.UpdateOptions{Notes: notes})
+	if err != nil {
+		return err
+	}
+
+	ui.Success("Updated release %s\n", release.Version)
+	return nil
+}
+
+// ReleasesPublishCommand is the releases publish command
+type ReleasesPublishCommand struct {
+	PackageOptions
+	Args ReleaseArgs `positional-args:"1" required:"1"`
+}
+
+// Execute runs the releases publish command
+func (c *ReleasesPublishCommand) Execute(args []string) error {
+	apiClient, game, gamePackage, release, err := GetReleaseParams(&c.PackageOptions, c.Args.Version)
+	if err != nil {
+		return err
+	}
+
+	release, err = apiClient.ReleasePublish(game.ID, gamePackage.ID, release.ID)
+	if err != nil {
+		return err
+	}
+
+	ui.Success("Published release %s\n", release.Version)
+	return nil
+}
+
+// ReleasesUnpublishCommand is the releases unpublish command
+type ReleasesUnpublishCommand struct {
+	PackageOptions
+	Args ReleaseArgs `positional-args:"1" required:"1"`
+}
+
+// Execute runs the releases unpublish command
+func (c *ReleasesUnpublishCommand) Execute(args []string) error {
+	apiClient, game, gamePackage, release, err := GetReleaseParams(&c.PackageOptions, c.Args.Version)
+	if err != nil {
+		return err
+	}
+
+	release, err = apiClient.ReleaseUnpublish(game.ID, gamePackage.ID, release.ID)
+	if err != nil {
+		return err
+	}
+
+	ui.Success("Unpublished release %s\n", release.Version)
+	return nil
+}
+
+// ReleasesDeleteCommand is the releases delete command
+type ReleasesDeleteCommand struct {
+	PackageOptions
+	Yes  bool        `short:"y" long:"yes" description:"Do not ask for confirmation"`
+	Args ReleaseArgs `positional-args:"1" required:"1"`
+}
+
+// Execute runs the releases delete command
+func (c *ReleasesDeleteCommand) Execute(args []string) error {
+	apiClient, game, gamePackage, release, err := GetReleaseParams(&c.PackageOptions, c.Args.Version)
+	if err != nil {
+		return err
+	}
+
+	if !c.Yes {
+		confirmed, err := Confirm(fmt.Sprintf("Delete release %s of %s along with all of its builds?", release.Version, gamePackage.Title))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			ui.Info("Not deleting anything\n")
+			return nil
+		}
+	}
+
+	if err = apiClient.ReleaseDelete(game.ID, gamePackage.ID, release.ID); err != nil {
+		return err
+	}
+
+	ui.Success("Deleted release %s\n", release.Version)
+	return nil
+}
+
+// GetReleaseParams is like GetPackageParams, but also finds the release with the given version in the package
+func GetReleaseParams(packageOpts *PackageOptions, releaseVersion string) (*api.Client, *models.Game, *models.GamePackage, *models.GameRelease, error) {
+	version, err := parseReleaseVersion(releaseVersion)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	apiClient, game, gamePackage, err := GetPackageParams(packageOpts)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	release, err := FindRelease(apiClient, game.ID, gamePackage.ID, version)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	return apiClient, game, gamePackage, release, nil
+}
+
+// ListAllReleases gets all the releases of a game package, going through all the pages
+func ListAllReleases(apiClient *api.Client, gameID, packageID int) ([]models.GameRelease, error) {
+	result := []models.GameRelease{}
+	for page := 1; ; page++ {
+		gameReleases, err := apiClient.Releases(&releases.ListReleasesOptions{GameID: gameID, PackageID: packageID, Page: page})
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, gameReleases.Releases...)
+		if len(gameReleases.Releases) == 0 || gameReleases.PerPage <= 0 || len(result) >= gameReleases.Total {
+			return result, nil
+		}
+	}
+}
+
+// FindRelease finds the release of a game package with the given version
+func FindRelease(apiClient *api.Client, gameID, packageID int, version *semver.Version) (*models.GameRelease, error) {
+	gameReleases, err := ListAllReleases(apiClient, gameID, packageID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, release := range gameReleases {
+		if releaseSemver, err := semver.Parse(release.Version); err == nil && releaseSemver.Equals(*version) {
+			return &gameReleases[i], nil
+		}
+	}
+
+	return nil, withExitCode(ExitNotFound, fmt.Errorf("Release %s does not exist", version.String()))
+}
+
+func parseReleaseVersion(releaseVersion string) (*semver.Version, error) {
+	version, err := semver.Parse(releaseVersion)
+	if err != nil {
+		return nil, withExitCode(ExitUsage, errors.New("Invalid semver. Check out https://semver.org"))
+	}
+	return &version, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	cliHttp "github.com/gamejolt/cli/pkg/http"
+	"github.com/gamejolt/cli/pkg/project"
+	"github.com/gamejolt/cli/pkg/ui"
+	"github.com/gamejolt/cli/pkg/update"
+
+	semver "github.com/blang/semver/v4"
+)
+
+// SelfUpdateCommand is the self-update command, which updates gjpush to the latest version
+type SelfUpdateCommand struct {
+	Check    bool   `long:"check" description:"Only check whether a newer version is available"`
+	IndexURL string `long:"index-url" value-name:"URL" description:"The url of the release index to update from"`
+}
+
+// Execute runs the self-update command
+func (c *SelfUpdateCommand) Execute(args []string) error {
+	if len(args) > 0 {
+		return withExitCode(ExitUsage, errors.New("Too many arguments!"))
+	}
+
+	settings := loadSettings()
+	transportOpts, err := GetTransportOptions(opts, settings)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	client, err := cliHttp.NewClient(transportOpts)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	index, err := update.FetchIndex(client, GetUpdateIndexURL(c.IndexURL, settings))
+	if err != nil {
+		return err
+	}
+
+	latest, err := index.Semver()
+	if err != nil {
+		return withExitCode(ExitUnexpectedResponse, err)
+	}
+
+	current := semver.MustParse(project.Version)
+	if !latest.GT(current) {
+		ui.Success("%s is up to date (%s)\n", project.Name, current.String())
+		return nil
+	}
+
+	ui.Info("A new version of %s is available: %s (current: %s)\n", project.Name, latest.String(), current.String())
+	if c.Check {
+		return nil
+	}
+
+	executable, err := os.Executable()
+	if err == nil {
+		executable, err = filepath.EvalSymlinks(executable)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to locate the %s executable: %w", project.Name, err)
+	}
+
+	binaryURL, err := index.BinaryURL(update.Platform())
+	if err != nil {
+		return withExitCode(ExitNotFound, err)
+	}
+
+	checksum, err := index.Checksum(client, binaryURL)
+	if err != nil {
+		return err
+	}
+
+	ui.Info("Downloading %s ...\n", binaryURL)
+	binary, err := update.Download(client, binaryURL, checksum, executable)
+	if err != nil {
+		return err
+	}
+
+	if err = update.Replace(executable, binary); err != nil {
+		os.Remove(binary)
+		return fmt.Errorf("Failed to replace %s: %w", executable, err)
+	}
+
+	ui.Success("Updated %s to %s\n", project.Name, latest.String())
+	return nil
+}
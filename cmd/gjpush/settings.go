@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gamejolt/cli/config"
+	cliHttp "github.com/gamejolt/cli/pkg/http"
+	"github.com/gamejolt/cli/pkg/ui"
+
+	color "github.com/fatih/color"
+)
+
+// Settings is the structure of the global settings file located in ~/.gj/config.json
+type Settings struct {
+	HTTP struct {
+		ConnectTimeout string   `json:"connect_timeout"`
+		ReadTimeout    string   `json:"read_timeout"`
+		Proxy          string   `json:"proxy"`
+		CACerts        []string `json:"ca_certs"`
+		ClientCert     string   `json:"client_cert"`
+		ClientKey      string   `json:"client_key"`
+		RateLimitWait  string   `json:"rate_limit_wait"`
+	} `json:"http"`
+	Update struct {
+		IndexURL string `json:"index_url"`
+	} `json:"update"`
+}
+
+// gjPath returns a path inside the global .gj folder in the user's home directory
+func gjPath(elem ...string) (string, error) {
+	dir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(append([]string{dir, ".gj"}, elem...)...), nil
+}
+
+// loadSettings reads the global settings file. A missing settings file is not an error.
+func loadSettings() *Settings {
+	settings := &Settings{}
+
+	settingsFile, err := gjPath("config.json")
+	if err != nil {
+		return settings
+	}
+
+	bytes, err := ioutil.ReadFile(settingsFile)
+	if err != nil {
+		return settings
+	}
+
+	if err = json.Unmarshal(bytes, settings); err != nil {
+		ui.Warn("Attempted to read the settings file (%s), but the file is malformed: %s\n", settingsFile, err.Error())
+		return &Settings{}
+	}
+
+	return settings
+}
+
+// transportWarnings are the transport warnings already printed. Every http client is set up with the same options, so they are only printed once.
+// The update check sets up its client in the background, so they are guarded by a lock.
+var (
+	transportWarnings     = map[string]bool{}
+	transportWarningsLock sync.Mutex
+)
+
+// warnTransport prints a warning about how an http client is set up to stderr, so it doesn't mix with the output of commands
+func warnTransport(message string) {
+	transportWarningsLock.Lock()
+	defer transportWarningsLock.Unlock()
+
+	if transportWarnings[message] {
+		return
+	}
+	transportWarnings[message] = true
+	ui.WarnCol(color.Error, "%s\n", message)
+}
+
+// GetTransportOptions merges the network options given on the command line with the ones in the settings file.
+// Options given on the command line take precedence.
+func GetTransportOptions(opts *Options, settings *Settings) (*cliHttp.TransportOptions, error) {
+	transportOpts := &cliHttp.TransportOptions{
+		ConnectTimeout: opts.Network.ConnectTimeout,
+		ReadTimeout:    opts.Network.ReadTimeout,
+		Proxy:          opts.Network.Proxy,
+		CACerts:        opts.Network.CACerts,
+		ClientCert:     opts.Network.ClientCert,
+		ClientKey:      opts.Network.ClientKey,
+		Warn:           warnTransport,
+	}
+
+	var err error
+	if transportOpts.ConnectTimeout == 0 && settings.HTTP.ConnectTimeout != "" {
+		if transportOpts.ConnectTimeout, err = time.ParseDuration(settings.HTTP.ConnectTimeout); err != nil {
+			return nil, fmt.Errorf("Invalid connect_timeout in the settings file: %s", err.Error())
+		}
+	}
+	if transportOpts.ReadTimeout == 0 && settings.HTTP.ReadTimeout != "" {
+		if transportOpts.ReadTimeout, err = time.ParseDuration(settings.HTTP.ReadTimeout); err != nil {
+			return nil, fmt.Errorf("Invalid read_timeout in the settings file: %s", err.Error())
+		}
+	}
+	if transportOpts.Proxy == "" {
+		transportOpts.Proxy = settings.HTTP.Proxy
+	}
+	if len(transportOpts.CACerts) == 0 {
+		transportOpts.CACerts = settings.HTTP.CACerts
+	}
+	if transportOpts.ClientCert == "" && transportOpts.ClientKey == "" {
+		transportOpts.ClientCert = settings.HTTP.ClientCert
+		transportOpts.ClientKey = settings.HTTP.ClientKey
+	}
+
+	return transportOpts, nil
+}
+
+// GetMaxRateLimitWait returns how long in total to wait out rate limits, as given on the command line or in the settings file.
+func GetMaxRateLimitWait(opts *Options, settings *Settings) (time.Duration, error) {
+	if opts.Network.RateLimitWait != nil {
+		return *opts.Network.RateLimitWait, nil
+	}
+
+	if settings.HTTP.RateLimitWait != "" {
+		wait, err := time.ParseDuration(settings.HTTP.RateLimitWait)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid rate_limit_wait in the settings file: %s", err.Error())
+		}
+		return wait, nil
+	}
+
+	return cliHttp.DefaultMaxRateLimitWait, nil
+}
+
+// GetUpdateIndexURL returns the url of the release index to update from, as given on the command line or in the settings file.
+func GetUpdateIndexURL(indexURL string, settings *Settings) string {
+	if indexURL != "" {
+		return indexURL
+	}
+	if settings.Update.IndexURL != "" {
+		return settings.Update.IndexURL
+	}
+	return config.UpdateIndexURL
+}
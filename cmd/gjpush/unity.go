@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gamejolt/cli/pkg/api/builds"
+	"github.com/gamejolt/cli/pkg/api/models"
+	"github.com/gamejolt/cli/pkg/fs"
+	"github.com/gamejolt/cli/pkg/inspect"
+	"github.com/gamejolt/cli/pkg/ui"
+)
+
+// unityTargets are the os fields of a build for the Unity build targets build folders are usually named after.
+// WebGL builds are browser builds, so they have no os field.
+var unityTargets = map[string]string{
+	"standalonewindows":      "windows",
+	"standalonewindows64":    "windows_64",
+	"standaloneosx":          "mac_64",
+	"standaloneosxintel64":   "mac_64",
+	"standaloneosxuniversal": "mac_64",
+	"standalonelinux":        "linux",
+	"standalonelinux64":      "linux_64",
+	"webgl":                  "",
+}
+
+// unityDoNotShipSuffixes are the suffixes of folders Unity puts next to the game that must not be shipped with it
+var unityDoNotShipSuffixes = []string{"_BackUpThisFolder_ButDontShipItWithYourGame", "_BurstDebugInformation_DoNotShip"}
+
+// UnityBuild is the output folder of a Unity build
+type UnityBuild struct {
+	Dir string
+
+	// Platform is the os field of a build the game runs on, or empty for WebGL builds
+	Platform string
+
+	// Executable is the path of the main executable in the folder. Empty for WebGL builds.
+	Executable string
+}
+
+// ReadUnityBuild works out the platform, architecture and main executable of a Unity build from its output folder.
+// The build target is taken from the name of the folder if it is named after one, like StandaloneWindows64, and from the files in it otherwise.
+func ReadUnityBuild(dir string) (*UnityBuild, error) {
+	stat, err := os.Stat(dir)
+	if err != nil || !stat.IsDir() {
+		return nil, withExitCode(ExitFileNotFound, fmt.Errorf("%s isn't a folder. Pass the folder Unity built the game to with --unity", dir))
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, withExitCode(ExitFileNotFound, err)
+	}
+
+	names := map[string]bool{}
+	for _, entry := range entries {
+		names[entry.Name()] = true
+	}
+
+	target, hasTarget := unityTargets[strings.ToLower(filepath.Base(filepath.Clean(dir)))]
+	build := &UnityBuild{Dir: dir}
+
+	// WebGL builds have an index.html next to the Build folder
+	if names["index.html"] && names["Build"] {
+		if hasTarget && target != "" {
+			return nil, withExitCode(ExitUsage, fmt.Errorf("%s is named after a desktop build target, but holds a WebGL build", dir))
+		}
+		return build, nil
+	}
+
+	// Desktop builds have the game's executable next to its data folder, or an app bundle on macOS
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case entry.IsDir() && strings.HasSuffix(name, ".app"):
+			build.Platform, build.Executable = "mac_64", name
+		case !entry.IsDir() && strings.HasSuffix(name, ".exe") && names[strings.TrimSuffix(name, ".exe")+"_Data"]:
+			build.Platform, build.Executable = "windows_64", name
+		case !entry.IsDir() && strings.HasSuffix(name, ".x86_64") && names[strings.TrimSuffix(name, ".x86_64")+"_Data"]:
+			build.Platform, build.Executable = "linux_64", name
+		case !entry.IsDir() && strings.HasSuffix(name, ".x86") && names[strings.TrimSuffix(name, ".x86")+"_Data"]:
+			build.Platform, build.Executable = "linux", name
+		}
+		if build.Executable != "" {
+			break
+		}
+	}
+
+	if build.Executable == "" {
+		if hasTarget {
+			return nil, withExitCode(ExitUsage, fmt.Errorf("%s is named after a Unity build target, but the game's executable isn't in it. Build the game to it in Unity first", dir))
+		}
+		return nil, withExitCode(ExitUsage, fmt.Errorf("Couldn't tell what Unity built to %s. Pass the folder Unity built the game to, like Builds/StandaloneWindows64", dir))
+	}
+
+	// Windows executables are named the same for both architectures, so the architecture is read from the executable's headers.
+	// If they can't be read, the build target name tells.
+	if build.Platform == "windows_64" {
+		if binaries, err := inspect.FindBinaries(filepath.Join(dir, build.Executable)); err == nil && len(binaries) > 0 {
+			build.Platform = binaries[0].Platform
+		} else if hasTarget && target != "" {
+			build.Platform = target
+		}
+	}
+	return build, nil
+}
+
+// skipDoNotShip returns whether a file in a build folder is one of the folders Unity puts next to the game that must not be shipped.
+// Used when zipping any build folder, since they can end up in one without it being pushed as a Unity build.
+func skipDoNotShip(rel string, info os.FileInfo) bool {
+	if !info.IsDir() || strings.Contains(rel, string(filepath.Separator)) {
+		return false
+	}
+
+	for _, suffix := range unityDoNotShipSuffixes {
+		if strings.HasSuffix(rel, suffix) {
+			ui.Info("Leaving out %s, Unity marks it as not to be shipped\n", rel)
+			return true
+		}
+	}
+	return false
+}
+
+// BuildUpdate returns the build fields that make a desktop build run on the build's platform, with its main executable as the launch option.
+// Returns nil for WebGL builds.
+func (b *UnityBuild) BuildUpdate() *builds.UpdateOptions {
+	if b.Platform == "" {
+		return nil
+	}
+	return PlatformUpdate([]string{b.Platform}, []models.GameBuildLaunchOption{{OS: b.Platform, ExecutablePath: b.Executable}})
+}
+
+// packUnityBuild zips the Unity build folder given as the file to push, and pushes the zip instead.
+// Returns the build fields to set once it is uploaded, and a function that removes the zip.
+func (c *PushCommand) packUnityBuild() (*builds.UpdateOptions, func(), error) {
+	build, err := ReadUnityBuild(c.Args.File)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if build.Platform == "" {
+		ui.Info("Found a Unity WebGL build\n")
+		c.IsBrowser = true
+	} else {
+		if c.IsBrowser {
+			return nil, nil, withExitCode(ExitUsage, errors.New("The Unity build is a desktop build, it can't be pushed as a browser build"))
+		}
+		ui.Info("Found a Unity %s build, with %s as the main executable\n", platformNames[build.Platform], build.Executable)
+	}
+
+	ui.Info("Zipping %s ...\n", build.Dir)
+	path, cleanup, err := fs.ZipFolderToTemp(build.Dir, skipDoNotShip)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to zip the Unity build: %w", err)
+	}
+	c.Args.File = path
+
+	ui.Info("\n")
+	return build.BuildUpdate(), cleanup, nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	cliHttp "github.com/gamejolt/cli/pkg/http"
+	"github.com/gamejolt/cli/pkg/project"
+	"github.com/gamejolt/cli/pkg/ui"
+	"github.com/gamejolt/cli/pkg/update"
+
+	semver "github.com/blang/semver/v4"
+	color "github.com/fatih/color"
+	flags "github.com/jessevdk/go-flags"
+)
+
+const (
+	// updateCheckInterval is how often to check for a new version
+	updateCheckInterval = 24 * time.Hour
+
+	// updateCheckTimeout is how long the check for a new version may take
+	updateCheckTimeout = 2 * time.Second
+)
+
+// ciEnvVars are environment variables set by CI services. Update checks are skipped when any of them is set.
+var ciEnvVars = []string{"CI", "CONTINUOUS_INTEGRATION", "BUILD_NUMBER", "GITHUB_ACTIONS", "GITLAB_CI", "TF_BUILD", "JENKINS_URL", "TEAMCITY_VERSION", "BUILDKITE"}
+
+// updateCheckCache is the structure of the update check cache located in ~/.gj/update-check.json
+type updateCheckCache struct {
+	CheckedAt int64  `json:"checked_at"`
+	Latest    string `json:"latest"`
+}
+
+// StartUpdateCheck checks whether a newer version of gjpush exists in the background, at most once a day.
+// The returned channel receives the newer version, or nil if there is none.
+func StartUpdateCheck(command flags.Commander) <-chan *semver.Version {
+	result := make(chan *semver.Version, 1)
+
+	// The output of the completion command is sourced by shells, so a notice after it would be run as a command
+	switch command.(type) {
+	case *SelfUpdateCommand, *CompletionCommand:
+		result <- nil
+		return result
+	}
+	if opts.Advanced.NoUpdateCheck || isCI() {
+		result <- nil
+		return result
+	}
+
+	go func() {
+		result <- checkForUpdate()
+	}()
+	return result
+}
+
+// PrintUpdateNotice prints a notice to stderr if the update check found a newer version, so that it doesn't mix into output scripts parse.
+// Doesn't wait for the update check for longer than it may take.
+func PrintUpdateNotice(updateCheck <-chan *semver.Version) {
+	select {
+	case latest := <-updateCheck:
+		if latest != nil {
+			ui.WarnCol(color.Error, "A new version of %s is available: %s (current: %s). Run '%s self-update' to update.\n", project.Name, latest.String(), project.Version, project.Name)
+		}
+	case <-time.After(updateCheckTimeout):
+	}
+}
+
+func checkForUpdate() *semver.Version {
+	cachePath, err := gjPath("update-check.json")
+	if err != nil {
+		return nil
+	}
+
+	cache := &updateCheckCache{}
+	if bytes, err := ioutil.ReadFile(cachePath); err == nil {
+		json.Unmarshal(bytes, cache)
+	}
+
+	if time.Since(time.Unix(cache.CheckedAt, 0)) >= updateCheckInterval {
+		if latest, err := fetchLatestVersion(); err == nil {
+			cache.Latest = latest.String()
+		}
+
+		// Remember failed checks too, so that being offline doesn't slow every command down
+		cache.CheckedAt = time.Now().Unix()
+		if bytes, err := json.Marshal(cache); err == nil && os.MkdirAll(filepath.Dir(cachePath), 0755) == nil {
+			ioutil.WriteFile(cachePath, bytes, 0644)
+		}
+	}
+
+	latest, err := semver.Parse(cache.Latest)
+	if err != nil || !latest.GT(semver.MustParse(project.Version)) {
+		return nil
+	}
+	return &latest
+}
+
+func fetchLatestVersion() (semver.Version, error) {
+	settings := loadSettings()
+	transportOpts, err := GetTransportOptions(opts, settings)
+	if err != nil {
+		return semver.Version{}, err
+	}
+
+	client, err := cliHttp.NewClient(transportOpts)
+	if err != nil {
+		return semver.Version{}, err
+	}
+	client.Timeout = updateCheckTimeout
+
+	index, err := update.FetchIndex(client, GetUpdateIndexURL("", settings))
+	if err != nil {
+		return semver.Version{}, err
+	}
+	return index.Semver()
+}
+
+func isCI() bool {
+	for _, envVar := range ciEnvVars {
+		if os.Getenv(envVar) != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gamejolt/cli/config"
+	"github.com/gamejolt/cli/pkg/api"
+	"github.com/gamejolt/cli/pkg/api/models"
+	"github.com/gamejolt/cli/pkg/fs"
+	"github.com/gamejolt/cli/pkg/ui"
+
+	semver "github.com/blang/semver/v4"
+)
+
+// releaseCounter is the placeholder in release templates that counts up with every push
+const releaseCounter = "{n}"
+
+// WatchCommand is the watch command, which pushes a build file or folder whenever it changes
+type WatchCommand struct {
+	PackageOptions
+	ReleaseTemplate string        `short:"r" long:"release" value-name:"TEMPLATE" description:"The release version to push to, where {n} counts up with every push, for example 1.4.0-playtest.{n}. By default bumps the prerelease version of the latest release."`
+	IsBrowser       bool          `short:"b" long:"browser" description:"Upload a browser build. By default uploads a desktop build."`
+	Force           bool          `long:"force" description:"Push browser builds even if checking them found issues."`
+	Interval        time.Duration `long:"interval" value-name:"DURATION" default:"2s" description:"How often to check for changes"`
+	Settle          time.Duration `long:"settle" value-name:"DURATION" default:"5s" description:"How long the build has to stop changing for before it is pushed"`
+	Args            struct {
+		Path string `positional-arg-name:"PATH" description:"The build file or folder to watch. Folders are zipped before they are pushed."`
+	} `positional-args:"1" required:"1"`
+}
+
+// Execute runs the watch command
+func (c *WatchCommand) Execute(args []string) error {
+	if len(args) > 0 {
+		return withExitCode(ExitUsage, errors.New("Too many arguments! Maybe you need to escape the path if it contains spaces?"))
+	}
+
+	var template *regexp.Regexp
+	if c.ReleaseTemplate != "" {
+		var err error
+		if template, err = parseReleaseTemplate(c.ReleaseTemplate); err != nil {
+			return withExitCode(ExitUsage, err)
+		}
+	}
+
+	apiClient, game, gamePackage, err := GetPackageParams(&c.PackageOptions)
+	if err != nil {
+		return err
+	}
+
+	path := c.Args.Path
+	pushed, err := snapshot(path)
+	if err != nil {
+		return err
+	}
+
+	ui.Info("Watching %s for changes. Press Ctrl+C to stop.\n", path)
+
+	pending := pushed
+	changedAt := time.Now()
+	for {
+		time.Sleep(c.Interval)
+
+		current, err := snapshot(path)
+		if err != nil {
+			ui.Warn("Failed to check %s for changes: %s\n", path, err.Error())
+			continue
+		}
+
+		// Wait for the build to stop changing before pushing it
+		if current != pending {
+			pending = current
+			changedAt = time.Now()
+			continue
+		}
+		if pending == "" || pending == pushed || time.Since(changedAt) < c.Settle {
+			continue
+		}
+
+		pushed = pending
+		if err = c.push(apiClient, game, gamePackage, template); err != nil {
+			ui.Error("Failed to push %s: %s\n", path, err.Error())
+		}
+		ui.Info("\nWatching %s for changes. Press Ctrl+C to stop.\n", path)
+	}
+}
+
+// push pushes the watched build to the next release
+func (c *WatchCommand) push(apiClient *api.Client, game *models.Game, gamePackage *models.GamePackage, template *regexp.Regexp) error {
+	path := c.Args.Path
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if stat.IsDir() {
+		ui.Info("Zipping %s ...\n", path)
+		zipPath, cleanup, err := fs.ZipFolderToTemp(path, skipDoNotShip)
+		if err != nil {
+			return fmt.Errorf("Failed to zip the build folder: %w", err)
+		}
+		defer cleanup()
+		path = zipPath
+	}
+
+	if c.IsBrowser {
+		if err = CheckBrowserBuild(path, c.Force); err != nil {
+			return err
+		}
+	}
+
+	filesize, checksum, err := getFileData(path)
+	if err != nil {
+		return err
+	}
+
+	releaseSemver, err := c.nextRelease(apiClient, game, gamePackage, template)
+	if err != nil {
+		return err
+	}
+
+	ui.Info("Pushing %s to release %s ...\n", c.Args.Path, releaseSemver.String())
+	fileStatus, err := apiClient.FileStatus(game.ID, filesize, checksum)
+	if err != nil {
+		return err
+	}
+
+	if _, err = StartUpload(apiClient, game, gamePackage, releaseSemver, c.IsBrowser, path, filesize, checksum, fileStatus, config.ChunkSize, false); err != nil {
+		return err
+	}
+
+	ui.Success("Pushed to release %s :D\n", releaseSemver.String())
+	return nil
+}
+
+// nextRelease gets the release version the next push goes to
+func (c *WatchCommand) nextRelease(apiClient *api.Client, game *models.Game, gamePackage *models.GamePackage, template *regexp.Regexp) (*semver.Version, error) {
+	if template == nil {
+		latest, err := findLatestRelease(apiClient, game.ID, gamePackage.ID)
+		if err != nil {
+			return nil, err
+		}
+		if latest == nil {
+			return nil, withExitCode(ExitNotFound, fmt.Errorf("There are no releases to bump yet. Pass a release template with -r to start from, for example -r \"1.0.0-playtest.%s\"", releaseCounter))
+		}
+
+		next, err := BumpVersion(*latest, "prerelease")
+		if err != nil {
+			return nil, err
+		}
+		return &next, nil
+	}
+
+	gameReleases, err := ListAllReleases(apiClient, game.ID, gamePackage.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	counter := uint64(0)
+	for _, release := range gameReleases {
+		match := template.FindStringSubmatch(release.Version)
+		if match == nil {
+			continue
+		}
+		if n, err := strconv.ParseUint(match[1], 10, 64); err == nil && n > counter {
+			counter = n
+		}
+	}
+
+	version, err := semver.Make(strings.Replace(c.ReleaseTemplate, releaseCounter, strconv.FormatUint(counter+1, 10), 1))
+	if err != nil {
+		return nil, withExitCode(ExitUsage, errors.New("Invalid semver. Check out https://semver.org"))
+	}
+	return &version, nil
+}
+
+// parseReleaseTemplate validates a release template, and returns a regex matching the versions made from it
+func parseReleaseTemplate(template string) (*regexp.Regexp, error) {
+	if strings.Count(template, releaseCounter) != 1 {
+		return nil, fmt.Errorf("The release template must contain %s exactly once, for example 1.4.0-playtest.%s", releaseCounter, releaseCounter)
+	}
+	if _, err := semver.Make(strings.Replace(template, releaseCounter, "1", 1)); err != nil {
+		return nil, errors.New("The release template is not a valid semver. Check out https://semver.org")
+	}
+
+	parts := strings.SplitN(template, releaseCounter, 2)
+	return regexp.MustCompile("^" + regexp.QuoteMeta(parts[0]) + `(\d+)` + regexp.QuoteMeta(parts[1]) + "$"), nil
+}
+
+// snapshot returns a fingerprint of a file or folder that changes whenever its contents change.
+// Returns an empty fingerprint if the path doesn't exist yet.
+func snapshot(path string) (string, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	hash := md5.New()
+	if !stat.IsDir() {
+		fmt.Fprintf(hash, "%d %d", stat.Size(), stat.ModTime().UnixNano())
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+
+	err = filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%s %d %d\n", filePath, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -11,3 +11,6 @@ const UploadHost = "development.gamejolt.com"
 // ChunkSize is the chunk size a file is uploaded in.
 // In development we split to 5 MB chunks.
 const ChunkSize = 5 * 1024 * 1024
+
+// UpdateIndexURL is the url of the release index gjpush updates itself from
+const UpdateIndexURL = "https://github.com/gamejolt/cli/releases/latest/download/index.json"
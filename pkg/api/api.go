@@ -3,13 +3,17 @@ package api
 import (
 	"io"
 	"net/http"
+	"runtime"
+	"time"
 
 	"github.com/gamejolt/cli/config"
+	"github.com/gamejolt/cli/pkg/api/builds"
 	"github.com/gamejolt/cli/pkg/api/files"
 	"github.com/gamejolt/cli/pkg/api/games"
 	"github.com/gamejolt/cli/pkg/api/me"
 	"github.com/gamejolt/cli/pkg/api/models"
 	"github.com/gamejolt/cli/pkg/api/packages"
+	"github.com/gamejolt/cli/pkg/api/releases"
 	cliHttp "github.com/gamejolt/cli/pkg/http"
 	"github.com/gamejolt/cli/pkg/project"
 
@@ -45,6 +49,27 @@ func NewClient(token string) *Client {
 	}
 }
 
+// SetHTTPClient sets the http client the api requests are sent through
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.client.Client = httpClient
+}
+
+// SetTransport sets the round tripper the api requests are sent through
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.client.Client = &http.Client{Transport: transport}
+}
+
+// SetMaxRateLimitWait sets how long in total the client may wait for the server to lift rate limits
+func (c *Client) SetMaxRateLimitWait(wait time.Duration) {
+	c.client.MaxRateLimitWait = wait
+}
+
+// SetTrace sets where every api request and response is logged to. The authorization token is never logged.
+func (c *Client) SetTrace(trace io.Writer) {
+	c.client.Trace = trace
+	c.client.Tracef("%s %s (%s/%s)\n", project.Name, project.Version, runtime.GOOS, runtime.GOARCH)
+}
+
 // Token returns the token this api client was created with
 func (c *Client) Token() string {
 	return c.token
@@ -61,8 +86,8 @@ func (c *Client) Game(gameID int) (*models.Game, error) {
 }
 
 // Games does a /games call
-func (c *Client) Games() (*games.Games, error) {
-	return games.List(c.client)
+func (c *Client) Games(options *games.ListOptions) (*games.Games, error) {
+	return games.List(c.client, options)
 }
 
 // GamePackage does a /packages/:packageId call
@@ -70,6 +95,66 @@ func (c *Client) GamePackage(packageID int, options *packages.GetOptions) (*mode
 	return packages.Get(c.client, packageID, options)
 }
 
+// GamePackages does a /packages call
+func (c *Client) GamePackages(options *packages.ListOptions) (*packages.Packages, error) {
+	return packages.List(c.client, options)
+}
+
+// GamePackageCreate does a POST /packages/create call
+func (c *Client) GamePackageCreate(gameID int, options *packages.SaveOptions) (*models.GamePackage, error) {
+	return packages.Create(c.client, gameID, options)
+}
+
+// GamePackageUpdate does a POST /packages/update/:packageId call
+func (c *Client) GamePackageUpdate(gameID, packageID int, options *packages.SaveOptions) (*models.GamePackage, error) {
+	return packages.Update(c.client, gameID, packageID, options)
+}
+
+// Releases does a /releases call
+func (c *Client) Releases(options *releases.ListReleasesOptions) (*releases.Releases, error) {
+	return releases.ListReleases(c.client, options)
+}
+
+// ReleaseCreate does a POST /releases/create call
+func (c *Client) ReleaseCreate(gameID, packageID int, options *releases.CreateOptions) (*models.GameRelease, error) {
+	return releases.Create(c.client, gameID, packageID, options)
+}
+
+// ReleaseUpdate does a POST /releases/update/:releaseId call
+func (c *Client) ReleaseUpdate(gameID, packageID, releaseID int, options *releases.UpdateOptions) (*models.GameRelease, error) {
+	return releases.Update(c.client, gameID, packageID, releaseID, options)
+}
+
+// ReleasePublish does a POST /releases/publish/:releaseId call
+func (c *Client) ReleasePublish(gameID, packageID, releaseID int) (*models.GameRelease, error) {
+	return releases.Publish(c.client, gameID, packageID, releaseID)
+}
+
+// ReleaseUnpublish does a POST /releases/unpublish/:releaseId call
+func (c *Client) ReleaseUnpublish(gameID, packageID, releaseID int) (*models.GameRelease, error) {
+	return releases.Unpublish(c.client, gameID, packageID, releaseID)
+}
+
+// ReleaseDelete does a POST /releases/delete/:releaseId call
+func (c *Client) ReleaseDelete(gameID, packageID, releaseID int) error {
+	return releases.Delete(c.client, gameID, packageID, releaseID)
+}
+
+// ReleaseBuilds does a /releases/builds/:releaseId call
+func (c *Client) ReleaseBuilds(releaseID int, options *releases.ListBuildsOptions) (*releases.Builds, error) {
+	return releases.List(c.client, releaseID, options)
+}
+
+// BuildUpdate does a POST /builds/update/:buildId call
+func (c *Client) BuildUpdate(gameID, packageID, buildID int, options *builds.UpdateOptions) (*models.GameBuild, error) {
+	return builds.Update(c.client, gameID, packageID, buildID, options)
+}
+
+// BuildDelete does a POST /builds/delete/:buildId call
+func (c *Client) BuildDelete(gameID, packageID, buildID int) error {
+	return builds.Delete(c.client, gameID, packageID, buildID)
+}
+
 // FileStatus does a GET /files/add call
 func (c *Client) FileStatus(gameID int, size int64, checksum string) (*files.GetResult, error) {
 	return files.Get(c.client, gameID, size, checksum)
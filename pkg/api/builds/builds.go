@@ -0,0 +1,74 @@
+package builds
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/gamejolt/cli/pkg/api/models"
+	"github.com/gamejolt/cli/pkg/api/response"
+	cliHttp "github.com/gamejolt/cli/pkg/http"
+)
+
+// DeleteResult is the payload from the `/builds/delete/:id` endpoint
+type DeleteResult struct {
+	Success bool          `json:"success"`
+	Error   *models.Error `json:"error,omitempty"`
+}
+
+// UpdateResult is the payload from the `/builds/update/:id` endpoint
+type UpdateResult struct {
+	Build *models.GameBuild `json:"build"`
+	Error *models.Error     `json:"error,omitempty"`
+}
+
+// UpdateOptions are the fields of a build to update. Fields that are nil are left as is.
+type UpdateOptions struct {
+	Windows       *bool                          `json:"os_windows,omitempty"`
+	Windows64     *bool                          `json:"os_windows_64,omitempty"`
+	Mac           *bool                          `json:"os_mac,omitempty"`
+	Mac64         *bool                          `json:"os_mac_64,omitempty"`
+	Linux         *bool                          `json:"os_linux,omitempty"`
+	Linux64       *bool                          `json:"os_linux_64,omitempty"`
+	Other         *bool                          `json:"os_other,omitempty"`
+	LaunchOptions []models.GameBuildLaunchOption `json:"launch_options,omitempty"`
+	EmbedWidth    *int                           `json:"embed_width,omitempty"`
+	EmbedHeight   *int                           `json:"embed_height,omitempty"`
+}
+
+func buildParams(gameID, packageID int) url.Values {
+	return url.Values(map[string][]string{
+		"game_id":    {strconv.Itoa(gameID)},
+		"package_id": {strconv.Itoa(packageID)},
+	})
+}
+
+// Update sends a new POST /builds/update/:buildId request
+func Update(client *cliHttp.SimpleClient, gameID, packageID, buildID int, options *UpdateOptions) (*models.GameBuild, error) {
+	_, res, err := client.Post(fmt.Sprintf("builds/update/%d", buildID), buildParams(gameID, packageID), options)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update build: %w", err)
+	}
+	defer res.Body.Close()
+
+	result := &UpdateResult{}
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to update build: %w", err)
+	}
+	return result.Build, nil
+}
+
+// Delete sends a new POST /builds/delete/:buildId request
+func Delete(client *cliHttp.SimpleClient, gameID, packageID, buildID int) error {
+	_, res, err := client.Post(fmt.Sprintf("builds/delete/%d", buildID), buildParams(gameID, packageID), nil)
+	if err != nil {
+		return fmt.Errorf("Failed to delete build: %w", err)
+	}
+	defer res.Body.Close()
+
+	result := &DeleteResult{}
+	if err = response.Decode(res, result); err != nil {
+		return fmt.Errorf("Failed to delete build: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,13 @@
 package errors
 
-import "github.com/gamejolt/cli/pkg/api/models"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gamejolt/cli/pkg/api/models"
+)
 
 const (
 	// MissingAuthorization is the error code for when the authorization header is missing in the api request.
@@ -20,10 +27,37 @@ const (
 	// This can happen for many reason, check the error message for more info.
 	CantAttachBuild = 5
 
+	// ChunkChecksumMismatch is the error code for when the uploaded chunk does not match the checksum sent along with it.
+	// This usually means the chunk got corrupted in transit, so it is safe to upload it again.
+	ChunkChecksumMismatch = 6
+
 	// UnknownError is the error code for any other unspecified error.
 	UnknownError = 1000
 )
 
+var (
+	// ErrUnauthorized is matched by errors for requests the server refused to authorize.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrNotFound is matched by errors for requests to a resource that doesn't exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrRateLimited is matched by errors for requests the server refused because too many requests were made.
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrServiceUnavailable is matched by errors for requests the server couldn't handle at the moment, for example during maintenance.
+	ErrServiceUnavailable = errors.New("service unavailable")
+
+	// ErrBadGateway is matched by errors for requests a gateway or proxy in front of the server failed to pass on.
+	ErrBadGateway = errors.New("bad gateway")
+
+	// ErrServer is matched by errors for requests the server failed to handle.
+	ErrServer = errors.New("server error")
+
+	// ErrUnexpectedResponse is matched by errors for responses that are not valid api payloads.
+	ErrUnexpectedResponse = errors.New("unexpected response")
+)
+
 // Error is an error type returned by the API calls
 type Error struct {
 	err *models.Error
@@ -47,3 +81,89 @@ func (e *Error) Code() int {
 func (e *Error) Fields() []string {
 	return e.err.Fields
 }
+
+// Is allows matching api errors against the error classes using errors.Is
+func (e *Error) Is(target error) bool {
+	switch e.err.Code {
+	case MissingAuthorization, InvalidAuthorization:
+		return target == ErrUnauthorized
+	}
+	return false
+}
+
+// HTTPError is an error type returned by the API calls when the server responded with something other than an api payload,
+// for example an html error page returned by a load balancer.
+type HTTPError struct {
+	StatusCode  int
+	ContentType string
+	Body        string
+}
+
+// NewHTTPError creates a new http error for the given response
+func NewHTTPError(res *http.Response, body []byte) *HTTPError {
+	return &HTTPError{
+		StatusCode:  res.StatusCode,
+		ContentType: res.Header.Get("Content-Type"),
+		Body:        string(body),
+	}
+}
+
+// Class returns the error class this error matches, one of the Err* errors
+func (e *HTTPError) Class() error {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode == http.StatusServiceUnavailable:
+		return ErrServiceUnavailable
+	case e.StatusCode == http.StatusBadGateway || e.StatusCode == http.StatusGatewayTimeout:
+		return ErrBadGateway
+	case e.StatusCode >= 500:
+		return ErrServer
+	}
+	return ErrUnexpectedResponse
+}
+
+// Is allows matching http errors against the error classes using errors.Is
+func (e *HTTPError) Is(target error) bool {
+	return target == e.Class()
+}
+
+func (e *HTTPError) Error() string {
+	status := fmt.Sprintf("HTTP %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+
+	switch e.Class() {
+	case ErrUnauthorized:
+		return fmt.Sprintf("the server refused the request, is your token valid? (%s)", status)
+	case ErrNotFound:
+		return fmt.Sprintf("the server could not find what we asked for (%s)", status)
+	case ErrRateLimited:
+		return fmt.Sprintf("too many requests, the server asked us to slow down (%s)", status)
+	case ErrServiceUnavailable:
+		return fmt.Sprintf("the server is temporarily unavailable, try again in a bit (%s)", status)
+	case ErrBadGateway:
+		return fmt.Sprintf("the server could not be reached, try again in a bit (%s)", status)
+	case ErrServer:
+		return fmt.Sprintf("the server failed to handle the request (%s)", status)
+	}
+
+	// Html pages are never useful to print in a terminal, but short plain responses might be.
+	snippet := strings.TrimSpace(e.Body)
+	if snippet == "" || strings.Contains(e.ContentType, "html") || strings.HasPrefix(snippet, "<") {
+		return fmt.Sprintf("the server returned a weird looking response (%s, %s)", status, e.contentType())
+	}
+	if len(snippet) > 200 {
+		snippet = snippet[:200] + "..."
+	}
+	return fmt.Sprintf("the server returned a weird looking response (%s, %s): %s", status, e.contentType(), snippet)
+}
+
+func (e *HTTPError) contentType() string {
+	if e.ContentType == "" {
+		return "no content type"
+	}
+	return e.ContentType
+}
@@ -1,15 +1,17 @@
 package files
 
 import (
-	"encoding/json"
+	"crypto/md5"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
-	"io/ioutil"
 	"net/url"
+	"os"
 	"strconv"
 
-	apiErrors "github.com/gamejolt/cli/pkg/api/errors"
 	"github.com/gamejolt/cli/pkg/api/models"
+	"github.com/gamejolt/cli/pkg/api/response"
 	cliHttp "github.com/gamejolt/cli/pkg/http"
 	customIO "github.com/gamejolt/cli/pkg/io"
 
@@ -43,6 +45,27 @@ func formatBool(b bool) string {
 	return "0"
 }
 
+// ChunkChecksum calculates the md5 checksum of a single chunk of a file.
+func ChunkChecksum(filepath string, startByte, chunkSize int64) (string, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	offset, err := file.Seek(startByte, 0)
+	if err != nil || offset != startByte {
+		return "", errors.New("Failed to seek the file, has it changed while I was running?")
+	}
+
+	hash := md5.New()
+	if _, err = io.Copy(hash, io.LimitReader(file, chunkSize)); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 // Get sends a new GET /files/add request
 func Get(client *cliHttp.SimpleClient, gameID int, size int64, checksum string) (*GetResult, error) {
 	getParams := url.Values(map[string][]string{
@@ -54,22 +77,13 @@ func Get(client *cliHttp.SimpleClient, gameID int, size int64, checksum string)
 	_, res, err := client.Get("files/add", getParams)
 
 	if err != nil {
-		return nil, errors.New("Failed to fetch current state of file on the server: " + err.Error())
+		return nil, fmt.Errorf("Failed to fetch current state of file on the server: %w", err)
 	}
 	defer res.Body.Close()
 
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, errors.New("Failed to fetch current state of file on the server: " + err.Error())
-	}
-
 	result := &GetResult{}
-	if err = json.Unmarshal(body, result); err != nil {
-		return nil, errors.New("Failed to fetch current state of file on the server, the server returned a weird looking response: " + string(body))
-	}
-
-	if result.Error != nil {
-		return nil, apiErrors.New(result.Error)
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to fetch current state of file on the server: %w", err)
 	}
 	return result, nil
 }
@@ -85,22 +99,13 @@ func Restart(client *cliHttp.SimpleClient, gameID int, size int64, checksum stri
 	_, res, err := client.Post("files/restart", getParams, nil)
 
 	if err != nil {
-		return nil, errors.New("Failed to restart file upload: " + err.Error())
+		return nil, fmt.Errorf("Failed to restart file upload: %w", err)
 	}
 	defer res.Body.Close()
 
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, errors.New("Failed to restart file upload: " + err.Error())
-	}
-
 	result := &RestartResult{}
-	if err = json.Unmarshal(body, result); err != nil {
-		return nil, errors.New("Failed to restart file upload, the server returned a weird looking response: " + string(body))
-	}
-
-	if result.Error != nil {
-		return nil, apiErrors.New(result.Error)
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to restart file upload: %w", err)
 	}
 	return result, nil
 }
@@ -117,6 +122,15 @@ func Add(client *cliHttp.SimpleClient, gameID, packageID int, releaseVersion *se
 		"restart":         {formatBool(forceRestart)},
 	})
 
+	// The chunk checksum is sent as a form field so the server can verify the chunk wasn't corrupted in transit.
+	chunkChecksum, err := ChunkChecksum(filepath, startByte, chunkSize)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to calculate checksum for the chunk: %w", err)
+	}
+	postParams := url.Values(map[string][]string{
+		"chunk_checksum": {chunkChecksum},
+	})
+
 	writeFileFunc := func(dst io.Writer, src cliHttp.MultipartFileEntry) (int64, error) {
 		offset, err := src.File.Seek(startByte, 0)
 		if err != nil || offset != startByte {
@@ -129,31 +143,24 @@ func Add(client *cliHttp.SimpleClient, gameID, packageID int, releaseVersion *se
 		// Limit the upload speed in development for testing
 		reader = customIO.NewReader(reader)
 
+		// The chunk may be sent more than once, so always count the progress from where the chunk starts.
+		bar.SetCurrent(startByte)
 		bar.SetTemplate(pb.Default)
 		reader = bar.NewProxyReader(reader)
 
 		return io.Copy(dst, reader)
 	}
 
-	_, res, err := client.Multipart("files/add", map[string]string{"file": filepath}, getParams, nil, writeFileFunc)
+	_, res, err := client.Multipart("files/add", map[string]string{"file": filepath}, getParams, postParams, writeFileFunc)
 
 	if err != nil {
-		return nil, errors.New("Failed to upload file: " + err.Error())
+		return nil, fmt.Errorf("Failed to upload file: %w", err)
 	}
 	defer res.Body.Close()
 
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, errors.New("Failed to upload file: " + err.Error())
-	}
-
 	result := &AddResult{}
-	if err = json.Unmarshal(body, result); err != nil {
-		return nil, errors.New("Failed to upload file, the server returned a weird looking response: " + string(body))
-	}
-
-	if result.Error != nil {
-		return nil, apiErrors.New(result.Error)
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to upload file: %w", err)
 	}
 	return result, nil
 }
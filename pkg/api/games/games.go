@@ -1,13 +1,12 @@
 package games
 
 import (
-	"encoding/json"
-	"errors"
-	"io/ioutil"
+	"fmt"
+	"net/url"
 	"strconv"
 
-	apiErrors "github.com/gamejolt/cli/pkg/api/errors"
 	"github.com/gamejolt/cli/pkg/api/models"
+	"github.com/gamejolt/cli/pkg/api/response"
 	cliHttp "github.com/gamejolt/cli/pkg/http"
 )
 
@@ -23,6 +22,11 @@ type ListResult struct {
 	Error *models.Error `json:"error,omitempty"`
 }
 
+// ListOptions are the parameters for the `/games` endpoint
+type ListOptions struct {
+	Page int
+}
+
 // Games is a list of games as returned by the /games endpoint
 type Games struct {
 	Games   []models.Game `json:"data"`
@@ -39,42 +43,29 @@ func Get(client *cliHttp.SimpleClient, gameID int) (*models.Game, error) {
 	}
 	defer res.Body.Close()
 
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, errors.New("Failed to fetch information about game: " + err.Error())
-	}
-
 	result := &GetResult{}
-	if err = json.Unmarshal(body, result); err != nil {
-		return nil, errors.New("Failed to fetch information about game, the server returned a weird looking response: " + string(body))
-	}
-
-	if result.Error != nil {
-		return nil, apiErrors.New(result.Error)
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to fetch information about game: %w", err)
 	}
 	return result.Game, nil
 }
 
 // List sends a new /games request
-func List(client *cliHttp.SimpleClient) (*Games, error) {
-	_, res, err := client.Get("games", nil)
-	if err != nil {
-		return nil, err
+func List(client *cliHttp.SimpleClient, options *ListOptions) (*Games, error) {
+	var getParams url.Values
+	if options != nil && options.Page != 0 {
+		getParams = url.Values{"page": {strconv.Itoa(options.Page)}}
 	}
-	defer res.Body.Close()
 
-	body, err := ioutil.ReadAll(res.Body)
+	_, res, err := client.Get("games", getParams)
 	if err != nil {
-		return nil, errors.New("Failed to list games: " + err.Error())
+		return nil, err
 	}
+	defer res.Body.Close()
 
 	result := &ListResult{}
-	if err = json.Unmarshal(body, result); err != nil {
-		return nil, errors.New("Failed to list games, the server returned a weird looking response: " + string(body))
-	}
-
-	if result.Error != nil {
-		return nil, apiErrors.New(result.Error)
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to list games: %w", err)
 	}
 	return result.Games, nil
 }
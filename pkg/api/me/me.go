@@ -1,12 +1,10 @@
 package me
 
 import (
-	"encoding/json"
-	"errors"
-	"io/ioutil"
+	"fmt"
 
-	apiErrors "github.com/gamejolt/cli/pkg/api/errors"
 	"github.com/gamejolt/cli/pkg/api/models"
+	"github.com/gamejolt/cli/pkg/api/response"
 	cliHttp "github.com/gamejolt/cli/pkg/http"
 )
 
@@ -20,22 +18,13 @@ type Result struct {
 func Send(client *cliHttp.SimpleClient) (*models.User, error) {
 	_, res, err := client.Get("me", nil)
 	if err != nil {
-		return nil, errors.New("Failed to authenticate: " + err.Error())
+		return nil, fmt.Errorf("Failed to authenticate: %w", err)
 	}
 	defer res.Body.Close()
 
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, errors.New("Failed to authenticate: " + err.Error())
-	}
-
 	result := &Result{}
-	if err = json.Unmarshal(body, result); err != nil {
-		return nil, errors.New("Failed to authenticate, the server returned a weird looking response: " + string(body))
-	}
-
-	if result.Error != nil {
-		return nil, apiErrors.New(result.Error)
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to authenticate: %w", err)
 	}
 
 	return result.User, nil
@@ -56,6 +56,7 @@ type GameRelease struct {
 
 // GameBuild is a build model
 type GameBuild struct {
+	ID                       int                     `json:"id"`
 	File                     *GameBuildFile          `json:"file"`
 	LaunchOptions            []GameBuildLaunchOption `json:"launch_options"`
 	ArchiveType              string                  `json:"archive_type"`
@@ -1,15 +1,12 @@
 package packages
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/url"
 	"strconv"
 
-	apiErrors "github.com/gamejolt/cli/pkg/api/errors"
 	"github.com/gamejolt/cli/pkg/api/models"
+	"github.com/gamejolt/cli/pkg/api/response"
 	cliHttp "github.com/gamejolt/cli/pkg/http"
 )
 
@@ -19,6 +16,34 @@ type GetResult struct {
 	Error   *models.Error       `json:"error,omitempty"`
 }
 
+// ListResult is the payload from the `/packages` endpoint
+type ListResult struct {
+	Packages *Packages     `json:"packages"`
+	Error    *models.Error `json:"error,omitempty"`
+}
+
+// ListOptions are the parameters for the `/packages` endpoint
+type ListOptions struct {
+	GameID int
+	Page   int
+}
+
+// Packages is a list of packages as returned by the /packages endpoint
+type Packages struct {
+	Packages []models.GamePackage `json:"data"`
+	Page     int                  `json:"page"`
+	PerPage  int                  `json:"per_page"`
+	Total    int                  `json:"total"`
+}
+
+// SaveOptions are the fields of a package to create or update. Fields that are nil are left as is.
+type SaveOptions struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Visibility  *string `json:"visibility,omitempty"`
+	Sort        *int    `json:"sort,omitempty"`
+}
+
 // GetOptions are additional optional parameters for the `/packages/:id` endpoint`
 type GetOptions struct {
 	GameID int
@@ -37,22 +62,73 @@ func Get(client *cliHttp.SimpleClient, packageID int, options *GetOptions) (*mod
 
 	_, res, err := client.Get(fmt.Sprintf("packages/%d", packageID), getParams)
 	if err != nil {
-		return nil, errors.New("Failed to get package: " + err.Error())
+		return nil, fmt.Errorf("Failed to get package: %w", err)
 	}
 	defer res.Body.Close()
 
-	body, err := ioutil.ReadAll(res.Body)
+	result := &GetResult{}
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to get package: %w", err)
+	}
+	return result.Package, nil
+}
+
+// List sends a new /packages request
+func List(client *cliHttp.SimpleClient, options *ListOptions) (*Packages, error) {
+	getParams := url.Values(map[string][]string{
+		"game_id": {strconv.Itoa(options.GameID)},
+	})
+	if options.Page != 0 {
+		getParams.Set("page", strconv.Itoa(options.Page))
+	}
+
+	_, res, err := client.Get("packages", getParams)
 	if err != nil {
-		return nil, errors.New("Failed to get package: " + err.Error())
+		return nil, fmt.Errorf("Failed to list packages: %w", err)
 	}
+	defer res.Body.Close()
+
+	result := &ListResult{}
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to list packages: %w", err)
+	}
+	return result.Packages, nil
+}
+
+// Create sends a new POST /packages/create request
+func Create(client *cliHttp.SimpleClient, gameID int, options *SaveOptions) (*models.GamePackage, error) {
+	getParams := url.Values(map[string][]string{
+		"game_id": {strconv.Itoa(gameID)},
+	})
+
+	_, res, err := client.Post("packages/create", getParams, options)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create package: %w", err)
+	}
+	defer res.Body.Close()
 
 	result := &GetResult{}
-	if err = json.Unmarshal(body, result); err != nil {
-		return nil, errors.New("Failed to get package, the server returned a weird looking response" + string(body))
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to create package: %w", err)
 	}
+	return result.Package, nil
+}
+
+// Update sends a new POST /packages/update/:packageId request
+func Update(client *cliHttp.SimpleClient, gameID, packageID int, options *SaveOptions) (*models.GamePackage, error) {
+	getParams := url.Values(map[string][]string{
+		"game_id": {strconv.Itoa(gameID)},
+	})
+
+	_, res, err := client.Post(fmt.Sprintf("packages/update/%d", packageID), getParams, options)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update package: %w", err)
+	}
+	defer res.Body.Close()
 
-	if result.Error != nil {
-		return nil, apiErrors.New(result.Error)
+	result := &GetResult{}
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to update package: %w", err)
 	}
 	return result.Package, nil
 }
@@ -1,15 +1,12 @@
 package releases
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/url"
 	"strconv"
 
-	apiErrors "github.com/gamejolt/cli/pkg/api/errors"
 	"github.com/gamejolt/cli/pkg/api/models"
+	"github.com/gamejolt/cli/pkg/api/response"
 	cliHttp "github.com/gamejolt/cli/pkg/http"
 )
 
@@ -23,6 +20,7 @@ type ListBuildsResult struct {
 type ListBuildsOptions struct {
 	GameID    int
 	PackageID int
+	Page      int
 }
 
 // Builds is a list of builds as returned by the /releases/builds/:id endpoint
@@ -42,27 +40,167 @@ func List(client *cliHttp.SimpleClient, releaseID int, options *ListBuildsOption
 			getMap["game_id"] = []string{strconv.Itoa(options.GameID)}
 			getMap["package_id"] = []string{strconv.Itoa(options.PackageID)}
 		}
+		if options.Page != 0 {
+			getMap["page"] = []string{strconv.Itoa(options.Page)}
+		}
 		getParams = url.Values(getMap)
 	}
 
 	_, res, err := client.Get(fmt.Sprintf("releases/builds/%d", releaseID), getParams)
 	if err != nil {
-		return nil, errors.New("Failed to get release: " + err.Error())
+		return nil, fmt.Errorf("Failed to get release: %w", err)
 	}
 	defer res.Body.Close()
 
-	body, err := ioutil.ReadAll(res.Body)
+	result := &ListBuildsResult{}
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to get release: %w", err)
+	}
+	return result.Builds, nil
+}
+
+// ListReleasesResult is the payload from the `/releases` endpoint
+type ListReleasesResult struct {
+	Releases *Releases     `json:"releases"`
+	Error    *models.Error `json:"error,omitempty"`
+}
+
+// ListReleasesOptions are the parameters for the `/releases` endpoint
+type ListReleasesOptions struct {
+	GameID    int
+	PackageID int
+	Page      int
+}
+
+// Releases is a list of releases as returned by the /releases endpoint
+type Releases struct {
+	Releases []models.GameRelease `json:"data"`
+	Page     int                  `json:"page"`
+	PerPage  int                  `json:"per_page"`
+	Total    int                  `json:"total"`
+}
+
+// ReleaseResult is the payload from the endpoints that return a single release
+type ReleaseResult struct {
+	Release *models.GameRelease `json:"release"`
+	Error   *models.Error       `json:"error,omitempty"`
+}
+
+// DeleteResult is the payload from the `/releases/delete/:id` endpoint
+type DeleteResult struct {
+	Success bool          `json:"success"`
+	Error   *models.Error `json:"error,omitempty"`
+}
+
+// CreateOptions are the fields of a new release
+type CreateOptions struct {
+	Version string `json:"version"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+// UpdateOptions are the fields of a release to update. Fields that are nil are left as is.
+type UpdateOptions struct {
+	Notes *string `json:"notes,omitempty"`
+}
+
+func packageParams(gameID, packageID int) url.Values {
+	return url.Values(map[string][]string{
+		"game_id":    {strconv.Itoa(gameID)},
+		"package_id": {strconv.Itoa(packageID)},
+	})
+}
+
+// ListReleases sends a new /releases request
+func ListReleases(client *cliHttp.SimpleClient, options *ListReleasesOptions) (*Releases, error) {
+	getParams := packageParams(options.GameID, options.PackageID)
+	if options.Page != 0 {
+		getParams.Set("page", strconv.Itoa(options.Page))
+	}
+
+	_, res, err := client.Get("releases", getParams)
 	if err != nil {
-		return nil, errors.New("Failed to get release: " + err.Error())
+		return nil, fmt.Errorf("Failed to list releases: %w", err)
 	}
+	defer res.Body.Close()
 
-	result := &ListBuildsResult{}
-	if err = json.Unmarshal(body, result); err != nil {
-		return nil, errors.New("Failed to get release, the server returned a weird looking response: " + string(body))
+	result := &ListReleasesResult{}
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to list releases: %w", err)
 	}
+	return result.Releases, nil
+}
 
-	if result.Error != nil {
-		return nil, apiErrors.New(result.Error)
+// Create sends a new POST /releases/create request
+func Create(client *cliHttp.SimpleClient, gameID, packageID int, options *CreateOptions) (*models.GameRelease, error) {
+	_, res, err := client.Post("releases/create", packageParams(gameID, packageID), options)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create release: %w", err)
 	}
-	return result.Builds, nil
+	defer res.Body.Close()
+
+	result := &ReleaseResult{}
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to create release: %w", err)
+	}
+	return result.Release, nil
+}
+
+// Update sends a new POST /releases/update/:id request
+func Update(client *cliHttp.SimpleClient, gameID, packageID, releaseID int, options *UpdateOptions) (*models.GameRelease, error) {
+	_, res, err := client.Post(fmt.Sprintf("releases/update/%d", releaseID), packageParams(gameID, packageID), options)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update release: %w", err)
+	}
+	defer res.Body.Close()
+
+	result := &ReleaseResult{}
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to update release: %w", err)
+	}
+	return result.Release, nil
+}
+
+// Publish sends a new POST /releases/publish/:id request
+func Publish(client *cliHttp.SimpleClient, gameID, packageID, releaseID int) (*models.GameRelease, error) {
+	_, res, err := client.Post(fmt.Sprintf("releases/publish/%d", releaseID), packageParams(gameID, packageID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to publish release: %w", err)
+	}
+	defer res.Body.Close()
+
+	result := &ReleaseResult{}
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to publish release: %w", err)
+	}
+	return result.Release, nil
+}
+
+// Unpublish sends a new POST /releases/unpublish/:id request
+func Unpublish(client *cliHttp.SimpleClient, gameID, packageID, releaseID int) (*models.GameRelease, error) {
+	_, res, err := client.Post(fmt.Sprintf("releases/unpublish/%d", releaseID), packageParams(gameID, packageID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unpublish release: %w", err)
+	}
+	defer res.Body.Close()
+
+	result := &ReleaseResult{}
+	if err = response.Decode(res, result); err != nil {
+		return nil, fmt.Errorf("Failed to unpublish release: %w", err)
+	}
+	return result.Release, nil
+}
+
+// Delete sends a new POST /releases/delete/:id request
+func Delete(client *cliHttp.SimpleClient, gameID, packageID, releaseID int) error {
+	_, res, err := client.Post(fmt.Sprintf("releases/delete/%d", releaseID), packageParams(gameID, packageID), nil)
+	if err != nil {
+		return fmt.Errorf("Failed to delete release: %w", err)
+	}
+	defer res.Body.Close()
+
+	result := &DeleteResult{}
+	if err = response.Decode(res, result); err != nil {
+		return fmt.Errorf("Failed to delete release: %w", err)
+	}
+	return nil
 }
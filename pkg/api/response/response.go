@@ -0,0 +1,61 @@
+package response
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	apiErrors "github.com/gamejolt/cli/pkg/api/errors"
+	"github.com/gamejolt/cli/pkg/api/models"
+)
+
+// errorPayload is the part of every api payload that holds the api error
+type errorPayload struct {
+	Error *models.Error `json:"error,omitempty"`
+}
+
+// Decode reads an api response and decodes its json payload into result.
+// Errors reported by the api in the payload are returned as an *apiErrors.Error.
+// Responses that are not api payloads at all, or that have an unexpected status code, are returned as an *apiErrors.HTTPError.
+func Decode(res *http.Response, result interface{}) error {
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if !isJSON(res) {
+		return apiErrors.NewHTTPError(res, body)
+	}
+
+	payload := &errorPayload{}
+	if err = json.Unmarshal(body, payload); err != nil {
+		return apiErrors.NewHTTPError(res, body)
+	}
+
+	// The api may return its errors along with any status code, they are always more descriptive than the status code.
+	if payload.Error != nil {
+		return apiErrors.New(payload.Error)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return apiErrors.NewHTTPError(res, body)
+	}
+
+	if err = json.Unmarshal(body, result); err != nil {
+		return apiErrors.NewHTTPError(res, body)
+	}
+	return nil
+}
+
+func isJSON(res *http.Response) bool {
+	contentType := res.Header.Get("Content-Type")
+
+	// Be lenient with responses that don't specify a content type and let the json decoding decide.
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || mediaType == "text/json")
+}
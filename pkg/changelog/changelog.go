@@ -0,0 +1,89 @@
+package changelog
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	semver "github.com/blang/semver/v4"
+)
+
+// headingRegex matches markdown headings, capturing their level and text
+var headingRegex = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+
+// fenceRegex matches the lines that open or close fenced code blocks, capturing the fence and what follows it
+var fenceRegex = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})(.*)$")
+
+// versionRegex matches a semver version inside a heading, for example "## 1.2.3", "## [v1.2.3] - 2020-01-01"
+var versionRegex = regexp.MustCompile(`v?(\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?)`)
+
+// ReadSection reads a changelog file and returns the notes of the section for the given version
+func ReadSection(path string, version semver.Version) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("Failed to read the release notes: %w", err)
+	}
+
+	notes, found := Section(string(content), version)
+	if !found {
+		return "", fmt.Errorf("There is no section for version %s in %s. Expected a heading like \"## %s\"", version.String(), path, version.String())
+	}
+	return notes, nil
+}
+
+// Section finds the section for the given version in a markdown changelog, and returns its contents without the heading.
+// Sections start with a heading containing the version, like "## 1.2.3", and end at the next heading of the same or higher level.
+// Lines in fenced code blocks are never headings.
+func Section(content string, version semver.Version) (string, bool) {
+	var lines []string
+	sectionLevel := 0
+	found := false
+	fence := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if match := fenceRegex.FindStringSubmatch(line); match != nil {
+			if fence == "" {
+				fence = match[1]
+			} else if match[1][0] == fence[0] && len(match[1]) >= len(fence) && strings.TrimSpace(match[2]) == "" {
+				// Code blocks are closed by a fence of the same kind, at least as long as the opening one
+				fence = ""
+			}
+		} else if match := headingRegex.FindStringSubmatch(line); match != nil && fence == "" {
+			level := len(match[1])
+
+			if found {
+				if level <= sectionLevel {
+					break
+				}
+			} else if headingVersion, ok := parseHeadingVersion(match[2]); ok && headingVersion.Equals(version) {
+				found = true
+				sectionLevel = level
+				continue
+			}
+		}
+
+		if found {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n")), found
+}
+
+func parseHeadingVersion(heading string) (semver.Version, bool) {
+	match := versionRegex.FindStringSubmatch(heading)
+	if match == nil {
+		return semver.Version{}, false
+	}
+
+	version, err := semver.Parse(match[1])
+	if err != nil {
+		return semver.Version{}, false
+	}
+	return version, true
+}
@@ -0,0 +1,79 @@
+package changelog
+
+import (
+	"testing"
+
+	semver "github.com/blang/semver/v4"
+)
+
+func TestSection(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		version string
+		notes   string
+		found   bool
+	}{
+		{
+			name:    "middle section",
+			content: "# Changelog\n\n## 1.2.0\n- New\n\n## 1.1.0\n- Old\n",
+			version: "1.2.0",
+			notes:   "- New",
+			found:   true,
+		},
+		{
+			name:    "last section",
+			content: "# Changelog\n\n## 1.2.0\n- New\n\n## 1.1.0\n- Old\n",
+			version: "1.1.0",
+			notes:   "- Old",
+			found:   true,
+		},
+		{
+			name:    "subheadings stay in the section",
+			content: "## [v1.2.0] - 2020-01-01\n### Fixed\n- Bug\n## 1.1.0\n- Old\n",
+			version: "1.2.0",
+			notes:   "### Fixed\n- Bug",
+			found:   true,
+		},
+		{
+			name:    "missing version",
+			content: "## 1.2.0\n- New\n",
+			version: "1.3.0",
+			notes:   "",
+			found:   false,
+		},
+		{
+			name:    "headings in backtick fences",
+			content: "## 1.2.0\n```sh\n# Install\ngjpush self-update\n```\n- New\n## 1.1.0\n- Old\n",
+			version: "1.2.0",
+			notes:   "```sh\n# Install\ngjpush self-update\n```\n- New",
+			found:   true,
+		},
+		{
+			name:    "headings in tilde fences",
+			content: "## 1.2.0\n~~~~\n## 1.1.0\n~~~\n~~~~\n- New\n## 1.1.0\n- Old\n",
+			version: "1.2.0",
+			notes:   "~~~~\n## 1.1.0\n~~~\n~~~~\n- New",
+			found:   true,
+		},
+		{
+			name:    "versions in fences aren't sections",
+			content: "## 1.2.0\n```\n## 1.1.0\n```\n",
+			version: "1.1.0",
+			notes:   "",
+			found:   false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			notes, found := Section(test.content, semver.MustParse(test.version))
+			if found != test.found {
+				t.Fatalf("expected found to be %v, got %v", test.found, found)
+			}
+			if notes != test.notes {
+				t.Errorf("expected notes %q, got %q", test.notes, notes)
+			}
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package fs
 
 import (
+	"archive/zip"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -43,3 +47,99 @@ func EnsureFolder(dir string) error {
 
 	return os.MkdirAll(dir, 0755)
 }
+
+// ZipFolder archives the contents of a folder into a zip file.
+// Paths in the archive are relative to the folder.
+func ZipFolder(dir, dest string) error {
+	return ZipFolderFiltered(dir, dest, nil)
+}
+
+// ZipFolderToTemp archives the contents of a folder into a zip file named after the folder, in a new temporary folder.
+// skip is passed on to ZipFolderFiltered. Returns the path of the zip file, and a function that removes it.
+func ZipFolderToTemp(dir string, skip func(rel string, info os.FileInfo) bool) (string, func(), error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tmpDir, err := ioutil.TempDir("", "gjpush")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(tmpDir) }
+
+	dest := filepath.Join(tmpDir, filepath.Base(absDir)+".zip")
+	if err = ZipFolderFiltered(absDir, dest, skip); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return dest, cleanup, nil
+}
+
+// ZipFolderFiltered archives the contents of a folder into a zip file, leaving out the files and folders skip returns true for.
+// skip is called with paths relative to the folder, and may be nil to archive everything.
+func ZipFolderFiltered(dir, dest string, skip func(rel string, info os.FileInfo) bool) (err error) {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	archive := zip.NewWriter(out)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		if skip != nil && rel != "." && skip(rel, info) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			if info, err = os.Stat(path); err != nil {
+				return err
+			}
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		header.Method = zip.Deflate
+
+		writer, err := archive.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(writer, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
@@ -0,0 +1,155 @@
+package godot
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PresetsFile is the name of the file Godot saves the export presets of a project to
+const PresetsFile = "export_presets.cfg"
+
+// sectionRegex matches the section headers of export_presets.cfg, like [preset.0] or [preset.0.options]
+var sectionRegex = regexp.MustCompile(`^\[preset\.(\d+)(\.options)?\]$`)
+
+// Preset is an export preset of a Godot project
+type Preset struct {
+	// Name is the name of the preset, like "Windows Desktop"
+	Name string
+
+	// Platform is the platform the preset exports to, like "Windows Desktop", "Linux/X11", "macOS" or "Web"
+	Platform string
+
+	// ExportPath is the path the preset exports to, relative to the project folder
+	ExportPath string
+
+	// Options are the platform specific options of the preset, with their values as written in the file
+	Options map[string]string
+}
+
+// ReadPresets reads the export presets of the Godot project in the given folder, in the order they are listed in Godot
+func ReadPresets(projectDir string) ([]Preset, error) {
+	path := filepath.Join(projectDir, PresetsFile)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	fields := map[int]map[string]string{}
+	options := map[int]map[string]string{}
+
+	var section map[string]string
+	var key, value string
+	flush := func() {
+		if section != nil && key != "" {
+			section[key] = value
+		}
+		key, value = "", ""
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// Strings and arrays can span several lines
+		if key != "" && !isComplete(value) {
+			value += "\n" + line
+			continue
+		}
+		flush()
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			section = nil
+			if match := sectionRegex.FindStringSubmatch(line); match != nil {
+				index, _ := strconv.Atoi(match[1])
+				sections := fields
+				if match[2] != "" {
+					sections = options
+				}
+				if sections[index] == nil {
+					sections[index] = map[string]string{}
+				}
+				section = sections[index]
+			}
+			continue
+		}
+
+		if separator := strings.Index(line, "="); separator != -1 {
+			key, value = strings.TrimSpace(line[:separator]), strings.TrimSpace(line[separator+1:])
+		}
+	}
+	flush()
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to read %s: %w", path, err)
+	}
+
+	indexes := []int{}
+	for index := range fields {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	presets := []Preset{}
+	for _, index := range indexes {
+		presetOptions := options[index]
+		if presetOptions == nil {
+			presetOptions = map[string]string{}
+		}
+
+		presets = append(presets, Preset{
+			Name:       unquote(fields[index]["name"]),
+			Platform:   unquote(fields[index]["platform"]),
+			ExportPath: unquote(fields[index]["export_path"]),
+			Options:    presetOptions,
+		})
+	}
+	return presets, nil
+}
+
+// Option returns the value of an option of the preset, with strings unquoted. Returns an empty string if the option isn't set.
+func (p *Preset) Option(name string) string {
+	return unquote(p.Options[name])
+}
+
+// isComplete returns whether a value has been read in full, meaning its strings are closed and its brackets balanced
+func isComplete(value string) bool {
+	inString, escaped := false, false
+	depth := 0
+	for _, char := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && char == '\\':
+			escaped = true
+		case char == '"':
+			inString = !inString
+		case inString:
+		case char == '(' || char == '[' || char == '{':
+			depth++
+		case char == ')' || char == ']' || char == '}':
+			depth--
+		}
+	}
+	return !inString && depth <= 0
+}
+
+// unquote returns the contents of a string value. Other values are returned as is.
+func unquote(value string) string {
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		return unquoted
+	}
+	return value
+}
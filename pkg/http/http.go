@@ -2,13 +2,21 @@ package http
 
 import (
 	"bytes"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/gamejolt/cli/config"
 	customIO "github.com/gamejolt/cli/pkg/io"
@@ -20,8 +28,22 @@ type SimpleClient struct {
 	// Base is the base to use for all urls. If empty, will use the given urls as is.
 	Base string
 
+	// Client is the http client requests are sent through. If nil, http.DefaultClient is used.
+	Client *http.Client
+
 	// NewRequest allows to customize which http.Request the simple client will use.
 	NewRequest func(method, urlStr string, body io.Reader) (*http.Request, error)
+
+	// MaxRateLimitWait is the total time the client may spend waiting for the server to lift a rate limit
+	// before giving up and returning the rate limited response. Zero disables waiting altogether.
+	MaxRateLimitWait time.Duration
+
+	// RateLimitWait is called to wait out a rate limit. Defaults to a visible countdown.
+	RateLimitWait func(delay time.Duration)
+
+	// Trace is where every request and response is logged to. If nil, nothing is logged.
+	Trace     io.Writer
+	traceLock sync.Mutex
 }
 
 // NewSimpleClient creates a new default client
@@ -31,13 +53,164 @@ func NewSimpleClient() *SimpleClient {
 		NewRequest: func(method, urlStr string, body io.Reader) (*http.Request, error) {
 			return http.NewRequest(method, urlStr, body)
 		},
+		MaxRateLimitWait: DefaultMaxRateLimitWait,
+		RateLimitWait:    countdown,
 	}
 	return client
 }
 
-func (c *SimpleClient) send(req *http.Request) (*http.Request, *http.Response, error) {
-	res, err := http.DefaultClient.Do(req)
-	return req, res, err
+// TransportOptions are the options used to create an http client with NewClient
+type TransportOptions struct {
+	// ConnectTimeout limits how long establishing a connection (including the TLS handshake) may take. Zero means no timeout.
+	ConnectTimeout time.Duration
+
+	// ReadTimeout limits how long to wait for the server's response headers once the request was sent. Zero means no timeout.
+	ReadTimeout time.Duration
+
+	// Proxy is the proxy url to use, for example http://proxy:8080 or socks5://proxy:1080.
+	// If empty, the proxy is taken from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+	Proxy string
+
+	// CACerts are paths to PEM encoded certificates to trust in addition to the system's root certificates.
+	// Where the system's root certificates can't be loaded, like on Windows, they are the only certificates trusted.
+	CACerts []string
+
+	// ClientCert and ClientKey are paths to a PEM encoded certificate and key to present to the server.
+	ClientCert string
+	ClientKey  string
+
+	// Warn is called with a message when the transport is set up differently than asked. Defaults to printing to stderr.
+	Warn func(message string)
+}
+
+// NewClient creates a new http client configured with the given transport options
+func NewClient(opts *TransportOptions) (*http.Client, error) {
+	transport, err := NewTransport(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// NewTransport creates a new http transport configured with the given transport options
+func NewTransport(opts *TransportOptions) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts == nil {
+		return transport, nil
+	}
+
+	if opts.ConnectTimeout > 0 {
+		transport.DialContext = (&net.Dialer{
+			Timeout:   opts.ConnectTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+		transport.TLSHandshakeTimeout = opts.ConnectTimeout
+	}
+	transport.ResponseHeaderTimeout = opts.ReadTimeout
+
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid proxy url: %s", err.Error())
+		}
+
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("Unsupported proxy scheme \"%s\", expected http, https or socks5", proxyURL.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if len(opts.CACerts) > 0 || opts.ClientCert != "" || opts.ClientKey != "" {
+		tlsConfig := &tls.Config{}
+
+		if len(opts.CACerts) > 0 {
+			// Go can't load the system's root certificates on Windows, so the CA certificates replace them there
+			pool, err := x509.SystemCertPool()
+			if err != nil || pool == nil {
+				warn := opts.Warn
+				if warn == nil {
+					warn = func(message string) { fmt.Fprintln(os.Stderr, message) }
+				}
+				warn("The system's root certificates couldn't be loaded, so only the given CA certificates are trusted")
+				pool = x509.NewCertPool()
+			}
+
+			for _, path := range opts.CACerts {
+				pem, err := ioutil.ReadFile(path)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to read CA certificate %s: %s", path, err.Error())
+				}
+				if !pool.AppendCertsFromPEM(pem) {
+					return nil, fmt.Errorf("No valid PEM certificates found in %s", path)
+				}
+			}
+			tlsConfig.RootCAs = pool
+		}
+
+		if opts.ClientCert != "" || opts.ClientKey != "" {
+			if opts.ClientCert == "" || opts.ClientKey == "" {
+				return nil, errors.New("Both a client certificate and a client key must be given")
+			}
+
+			cert, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to load client certificate: %s", err.Error())
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return transport, nil
+}
+
+// requestMaker creates a new request every time it is called.
+// Requests may be sent more than once, for example when waiting out a rate limit, so their bodies have to be created anew.
+type requestMaker func() (*http.Request, error)
+
+func (c *SimpleClient) send(makeRequest requestMaker) (*http.Request, *http.Response, error) {
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var waited time.Duration
+	for {
+		req, err := makeRequest()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		c.traceRequest(req)
+		start := time.Now()
+
+		res, err := client.Do(req)
+		c.traceResponse(res, err, start)
+		if err != nil {
+			return req, res, err
+		}
+
+		delay, ok := retryAfter(res, time.Now())
+		if !ok || waited+delay > c.MaxRateLimitWait {
+			return req, res, nil
+		}
+
+		// Discard the rate limited response and try again once the server is ready for us.
+		io.Copy(ioutil.Discard, res.Body)
+		res.Body.Close()
+
+		c.Tracef("Rate limited, retrying in %s\n", delay)
+		waited += delay
+		if c.RateLimitWait != nil {
+			c.RateLimitWait(delay)
+		} else {
+			time.Sleep(delay)
+		}
+	}
 }
 
 func (c *SimpleClient) getURL(urlStr string, forUpload bool) (*url.URL, error) {
@@ -86,12 +259,9 @@ func (c *SimpleClient) Get(urlStr string, params url.Values) (*http.Request, *ht
 		return nil, nil, err
 	}
 
-	req, err := c.NewRequest("GET", urlStr, nil)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return c.send(req)
+	return c.send(func() (*http.Request, error) {
+		return c.NewRequest("GET", urlStr, nil)
+	})
 }
 
 // Post does an http post of type application/json
@@ -106,13 +276,15 @@ func (c *SimpleClient) Post(urlStr string, get url.Values, post interface{}) (*h
 		return nil, nil, err
 	}
 
-	req, err := c.NewRequest("POST", urlStr, bytes.NewBuffer(jsonBytes))
-	if err != nil {
-		return nil, nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
+	return c.send(func() (*http.Request, error) {
+		req, err := c.NewRequest("POST", urlStr, bytes.NewBuffer(jsonBytes))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
 
-	return c.send(req)
+		return req, nil
+	})
 }
 
 // MultipartFileEntry is a multipart file entry.
@@ -156,11 +328,18 @@ func (c *SimpleClient) uploadMultipartEntries(fileEntries []MultipartFileEntry,
 			return
 		}
 
+		var written int64
 		if writeFileCallback == nil {
-			_, err = io.Copy(fileField, customIO.NewReader(fileEntry.File))
+			written, err = io.Copy(fileField, customIO.NewReader(fileEntry.File))
 		} else {
-			_, err = writeFileCallback(fileField, fileEntry)
+			written, err = writeFileCallback(fileField, fileEntry)
+		}
+
+		// The file contents are never traced, only which part of the file was sent.
+		if end, seekErr := fileEntry.File.Seek(0, io.SeekCurrent); seekErr == nil {
+			c.Tracef("    file %s: %s, bytes %d-%d (%d bytes)", fileEntry.Param, fileEntry.Path, end-written, end, written)
 		}
+
 		fileEntry.File.Close()
 		if err != nil {
 			return
@@ -175,38 +354,42 @@ func (c *SimpleClient) Multipart(urlStr string, files map[string]string, get, po
 		return nil, nil, err
 	}
 
-	fileEntries, err := c.makeMultipartEntries(files)
-	if err != nil {
-		return nil, nil, err
-	}
+	return c.send(func() (*http.Request, error) {
+		fileEntries, err := c.makeMultipartEntries(files)
+		if err != nil {
+			return nil, err
+		}
 
-	reqReader, reqWriter := io.Pipe()
-	multipartWriter := multipart.NewWriter(reqWriter)
+		reqReader, reqWriter := io.Pipe()
+		multipartWriter := multipart.NewWriter(reqWriter)
 
-	go func() {
-		for key, values := range post {
-			if len(values) == 1 {
-				if err := multipartWriter.WriteField(key, values[0]); err != nil {
-					return
-				}
-			} else {
-				key += "[]"
-				for _, value := range values {
-					if err := multipartWriter.WriteField(key, value); err != nil {
+		go func() {
+			for key, values := range post {
+				if len(values) == 1 {
+					c.Tracef("    field %s: %s", key, values[0])
+					if err := multipartWriter.WriteField(key, values[0]); err != nil {
 						return
 					}
+				} else {
+					key += "[]"
+					for _, value := range values {
+						c.Tracef("    field %s: %s", key, value)
+						if err := multipartWriter.WriteField(key, value); err != nil {
+							return
+						}
+					}
 				}
 			}
-		}
 
-		c.uploadMultipartEntries(fileEntries, reqWriter, multipartWriter, writeFileCallback)
-	}()
+			c.uploadMultipartEntries(fileEntries, reqWriter, multipartWriter, writeFileCallback)
+		}()
 
-	req, err := c.NewRequest("POST", urlStr, reqReader)
-	if err != nil {
-		return nil, nil, err
-	}
-	req.Header.Set("Content-Type", multipartWriter.FormDataContentType())
+		req, err := c.NewRequest("POST", urlStr, reqReader)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", multipartWriter.FormDataContentType())
 
-	return c.send(req)
+		return req, nil
+	})
 }
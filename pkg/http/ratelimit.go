@@ -0,0 +1,70 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gamejolt/cli/pkg/ui"
+)
+
+// DefaultMaxRateLimitWait is the default total time a client may spend waiting out rate limits.
+const DefaultMaxRateLimitWait = 5 * time.Minute
+
+// defaultRateLimitDelay is how long to wait when the server rate limits us without saying for how long.
+const defaultRateLimitDelay = 10 * time.Second
+
+// retryAfter returns how long to wait before retrying a rate limited response.
+// Returns false if the response is not rate limited.
+func retryAfter(res *http.Response, now time.Time) (time.Duration, bool) {
+	header := strings.TrimSpace(res.Header.Get("Retry-After"))
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests:
+		if header == "" {
+			return defaultRateLimitDelay, true
+		}
+
+	// A 503 is only a rate limit if the server tells us when to come back, otherwise it is a plain outage.
+	case http.StatusServiceUnavailable:
+		if header == "" {
+			return 0, false
+		}
+
+	default:
+		return 0, false
+	}
+
+	// Retry-After is either a number of seconds or an http date.
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			seconds = 0
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return defaultRateLimitDelay, true
+}
+
+// countdown waits for the given delay while showing how long is left.
+func countdown(delay time.Duration) {
+	end := time.Now().Add(delay)
+	for left := delay; left > 0; left = time.Until(end) {
+		ui.Warn("\rThe server asked us to slow down, retrying in %ds ... ", int(left.Round(time.Second)/time.Second))
+
+		if left > time.Second {
+			left = time.Second
+		}
+		time.Sleep(left)
+	}
+	ui.Warn("\r%s\r", strings.Repeat(" ", 60))
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxTracedBodySize is the maximum amount of bytes of a request or response body that gets written to the trace log.
+const maxTracedBodySize = 64 * 1024
+
+// redactedHeaders are headers whose values must never end up in a trace log.
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// Tracef writes a line to the trace log, if tracing is enabled.
+func (c *SimpleClient) Tracef(format string, a ...interface{}) {
+	if c.Trace == nil {
+		return
+	}
+
+	c.traceLock.Lock()
+	defer c.traceLock.Unlock()
+	fmt.Fprintf(c.Trace, format+"\n", a...)
+}
+
+func (c *SimpleClient) traceRequest(req *http.Request) {
+	if c.Trace == nil {
+		return
+	}
+
+	c.Tracef("--> %s %s", req.Method, req.URL.String())
+	c.traceHeaders(req.Header)
+
+	// Only json bodies are logged as is. Multipart bodies are logged as they are written, without the file contents.
+	if req.GetBody != nil && strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+		if body, err := req.GetBody(); err == nil {
+			if bodyBytes, err := ioutil.ReadAll(body); err == nil {
+				c.traceBody(bodyBytes)
+			}
+		}
+	}
+}
+
+func (c *SimpleClient) traceResponse(res *http.Response, err error, start time.Time) {
+	if c.Trace == nil {
+		return
+	}
+
+	if err != nil {
+		c.Tracef("<-- error after %s: %s", time.Since(start).Round(time.Millisecond), err.Error())
+		return
+	}
+
+	// Buffer the body so it can be both logged and read by the caller.
+	body, readErr := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	c.Tracef("<-- %s (%s)", res.Status, time.Since(start).Round(time.Millisecond))
+	c.traceHeaders(res.Header)
+	if readErr != nil {
+		c.Tracef("    failed to read the response body: %s", readErr.Error())
+	}
+	c.traceBody(body)
+	c.Tracef("")
+}
+
+func (c *SimpleClient) traceHeaders(headers http.Header) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := strings.Join(headers[name], ", ")
+		if redactedHeaders[http.CanonicalHeaderKey(name)] {
+			value = "[redacted]"
+		}
+		c.Tracef("    %s: %s", name, value)
+	}
+}
+
+func (c *SimpleClient) traceBody(body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	if len(body) > maxTracedBodySize {
+		c.Tracef("    %s\n    ... (%d more bytes)", body[:maxTracedBodySize], len(body)-maxTracedBodySize)
+		return
+	}
+	c.Tracef("    %s", body)
+}
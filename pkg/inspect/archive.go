@@ -0,0 +1,96 @@
+package inspect
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// walkFunc is called for every file in a build with its path in the build and its size.
+// open reads the contents of the file, and can only be called until walkFunc returns.
+type walkFunc func(name string, size int64, open func() (io.ReadCloser, error)) error
+
+// isArchive returns whether a build file is an archive walkArchive can look into
+func isArchive(path string) bool {
+	lower := strings.ToLower(path)
+	for _, ext := range []string{".zip", ".tar", ".tar.gz", ".tgz", ".tar.bz2", ".tbz2"} {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// walkArchive calls fn for every file in an archive
+func walkArchive(path string, fn walkFunc) error {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return walkZip(path, fn)
+	case strings.HasSuffix(lower, ".tar"):
+		return walkTar(path, nil, fn)
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return walkTar(path, func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }, fn)
+	case strings.HasSuffix(lower, ".tar.bz2"), strings.HasSuffix(lower, ".tbz2"):
+		return walkTar(path, func(r io.Reader) (io.Reader, error) { return bzip2.NewReader(r), nil }, fn)
+	}
+	return fmt.Errorf("%s isn't an archive", path)
+}
+
+func walkZip(path string, fn walkFunc) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		if err = fn(file.Name, int64(file.UncompressedSize64), file.Open); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkTar(path string, decompress func(io.Reader) (io.Reader, error), fn walkFunc) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if decompress != nil {
+		if reader, err = decompress(file); err != nil {
+			return err
+		}
+	}
+
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+
+		open := func() (io.ReadCloser, error) { return ioutil.NopCloser(archive), nil }
+		if err = fn(strings.TrimPrefix(header.Name, "./"), header.Size, open); err != nil {
+			return err
+		}
+	}
+}
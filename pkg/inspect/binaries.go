@@ -0,0 +1,328 @@
+package inspect
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gamejolt/cli/pkg/api/models"
+)
+
+// helperRegex matches the names of executables that ship next to the game, but aren't the game itself
+var helperRegex = regexp.MustCompile(`(?i)(crashhandler|crashpad|unins\d*|vc_?redist|dxsetup|dotnetfx|setup|helper)`)
+
+// Binary is a native binary found in a build
+type Binary struct {
+	// Path is the path of the binary in the build
+	Path string
+
+	// Platform is the platform the binary runs on, named like the os fields of a build:
+	// windows, windows_64, mac, mac_64, linux or linux_64
+	Platform string
+
+	// Library is whether the binary is a library rather than an executable
+	Library bool
+}
+
+// FindBinaries finds the native binaries in a build by reading their PE, ELF and Mach-O headers.
+// Archives are searched file by file. Only the headers of the files are read, never their whole contents.
+func FindBinaries(buildPath string) ([]Binary, error) {
+	if !isArchive(buildPath) {
+		return findFileBinaries(buildPath)
+	}
+
+	binaries := []Binary{}
+	err := walkArchive(buildPath, func(name string, size int64, open func() (io.ReadCloser, error)) error {
+		reader, err := open()
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+
+		// Files in archives can only be read from start to end, which is the order the headers are read in
+		binaries = append(binaries, parseBinary(name, &forwardReader{reader: reader})...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return binaries, nil
+}
+
+// findFileBinaries finds the binaries in a build that is a single file on disk
+func findFileBinaries(path string) ([]Binary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	binaries := parseBinary(filepath.Base(path), file)
+	if binaries == nil {
+		binaries = []Binary{}
+	}
+	return binaries, nil
+}
+
+// forwardReaderHeadSize is how much of the start of a file forwardReader keeps, which holds the main headers of binaries
+const forwardReaderHeadSize = 4096
+
+// forwardReader reads a stream as an io.ReaderAt. The start of the stream is kept so it can be read again,
+// the rest can only be read from start to end, and the bytes between reads are skipped without being kept.
+type forwardReader struct {
+	reader io.Reader
+	head   []byte
+	offset int64
+}
+
+func (r *forwardReader) ReadAt(p []byte, off int64) (int, error) {
+	if r.head == nil {
+		r.head = make([]byte, forwardReaderHeadSize)
+		n, err := io.ReadFull(r.reader, r.head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return 0, err
+		}
+		r.head, r.offset = r.head[:n], int64(n)
+	}
+
+	n := 0
+	if off < int64(len(r.head)) {
+		n = copy(p, r.head[off:])
+		off += int64(n)
+	}
+	if n == len(p) {
+		return n, nil
+	}
+
+	if off < r.offset {
+		return n, errors.New("Can't read backwards")
+	}
+	if off > r.offset {
+		skipped, err := io.CopyN(ioutil.Discard, r.reader, off-r.offset)
+		r.offset += skipped
+		if err != nil {
+			return n, err
+		}
+	}
+
+	read, err := io.ReadFull(r.reader, p[n:])
+	r.offset += int64(read)
+	return n + read, err
+}
+
+// parseBinary reads the headers of a binary. Past the start of the file, they are read in the order they are in the file.
+// Fat Mach-O binaries result in a binary for every architecture they hold. Returns nil if the file isn't a binary.
+func parseBinary(name string, reader io.ReaderAt) []Binary {
+	magic := make([]byte, 4)
+	if _, err := reader.ReadAt(magic, 0); err != nil {
+		return nil
+	}
+
+	switch {
+	case magic[0] == 'M' && magic[1] == 'Z':
+		return parsePE(name, reader)
+	case bytes.Equal(magic, []byte(elf.ELFMAG)):
+		return parseELF(name, reader)
+	case binary.BigEndian.Uint32(magic) == macho.MagicFat:
+		return parseFatMachO(name, reader)
+	}
+
+	if bin, ok := parseMachO(name, reader, 0); ok {
+		return []Binary{bin}
+	}
+	return nil
+}
+
+// parsePE reads the COFF header of a Windows binary, which the DOS header points to
+func parsePE(name string, reader io.ReaderAt) []Binary {
+	dosHeader := make([]byte, 64)
+	if _, err := reader.ReadAt(dosHeader, 0); err != nil {
+		return nil
+	}
+
+	// The PE signature is followed by the COFF header, with the machine first and the characteristics last
+	header := make([]byte, 24)
+	if _, err := reader.ReadAt(header, int64(binary.LittleEndian.Uint32(dosHeader[0x3c:]))); err != nil || !bytes.Equal(header[:4], []byte("PE\x00\x00")) {
+		return nil
+	}
+
+	machine, characteristics := binary.LittleEndian.Uint16(header[4:]), binary.LittleEndian.Uint16(header[22:])
+	platform := "windows"
+	if machine == pe.IMAGE_FILE_MACHINE_AMD64 || machine == pe.IMAGE_FILE_MACHINE_ARM64 {
+		platform = "windows_64"
+	}
+	return []Binary{{Path: name, Platform: platform, Library: characteristics&pe.IMAGE_FILE_DLL != 0}}
+}
+
+// parseELF reads the ELF header of a Linux binary, and its program headers to tell executables from libraries
+func parseELF(name string, reader io.ReaderAt) []Binary {
+	header := make([]byte, 64)
+	if _, err := reader.ReadAt(header[:52], 0); err != nil {
+		return nil
+	}
+
+	var order binary.ByteOrder
+	switch elf.Data(header[elf.EI_DATA]) {
+	case elf.ELFDATA2LSB:
+		order = binary.LittleEndian
+	case elf.ELFDATA2MSB:
+		order = binary.BigEndian
+	default:
+		return nil
+	}
+
+	var platform string
+	var progOffset int64
+	var progSize, progCount uint16
+	switch elf.Class(header[elf.EI_CLASS]) {
+	case elf.ELFCLASS32:
+		platform = "linux"
+		progOffset = int64(order.Uint32(header[28:]))
+		progSize, progCount = order.Uint16(header[42:]), order.Uint16(header[44:])
+	case elf.ELFCLASS64:
+		if _, err := reader.ReadAt(header[52:], 52); err != nil {
+			return nil
+		}
+		platform = "linux_64"
+		progOffset = int64(order.Uint64(header[32:]))
+		progSize, progCount = order.Uint16(header[54:]), order.Uint16(header[56:])
+	default:
+		return nil
+	}
+
+	// Position independent executables are shared objects too, but unlike libraries they ask for an interpreter
+	fileType := elf.Type(order.Uint16(header[16:]))
+	library := fileType == elf.ET_DYN
+	if library && progCount > 0 && progSize >= 4 && progOffset >= int64(len(header)) {
+		progs := make([]byte, int(progSize)*int(progCount))
+		if _, err := reader.ReadAt(progs, progOffset); err == nil {
+			for i := 0; i < len(progs); i += int(progSize) {
+				if elf.ProgType(order.Uint32(progs[i:])) == elf.PT_INTERP {
+					library = false
+				}
+			}
+		}
+	}
+	return []Binary{{Path: name, Platform: platform, Library: library || fileType == elf.ET_REL}}
+}
+
+// parseFatMachO reads the headers of every architecture in a fat Mach-O binary
+func parseFatMachO(name string, reader io.ReaderAt) []Binary {
+	header := make([]byte, 8)
+	if _, err := reader.ReadAt(header, 0); err != nil {
+		return nil
+	}
+
+	// Java class files start with the same magic, followed by their version instead of a handful of architectures
+	count := binary.BigEndian.Uint32(header[4:])
+	if count == 0 || count > 32 {
+		return nil
+	}
+
+	arches := make([]byte, 20*count)
+	if _, err := reader.ReadAt(arches, int64(len(header))); err != nil {
+		return nil
+	}
+
+	offsets := []int64{}
+	for i := 0; i < len(arches); i += 20 {
+		offsets = append(offsets, int64(binary.BigEndian.Uint32(arches[i+8:])))
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+
+	binaries := []Binary{}
+	for _, offset := range offsets {
+		bin, ok := parseMachO(name, reader, offset)
+		if !ok {
+			return nil
+		}
+		binaries = append(binaries, bin)
+	}
+	return binaries
+}
+
+// parseMachO reads the Mach-O header at the given offset of a macOS binary
+func parseMachO(name string, reader io.ReaderAt, offset int64) (Binary, bool) {
+	header := make([]byte, 16)
+	if _, err := reader.ReadAt(header, offset); err != nil {
+		return Binary{}, false
+	}
+
+	var order binary.ByteOrder
+	be, le := binary.BigEndian.Uint32(header), binary.LittleEndian.Uint32(header)
+	switch {
+	case be == macho.Magic32 || be == macho.Magic64:
+		order = binary.BigEndian
+	case le == macho.Magic32 || le == macho.Magic64:
+		order = binary.LittleEndian
+	default:
+		return Binary{}, false
+	}
+
+	cpu, fileType := macho.Cpu(order.Uint32(header[4:])), macho.Type(order.Uint32(header[12:]))
+	platform := "mac"
+	if cpu == macho.CpuAmd64 || cpu == macho.CpuArm64 || cpu == macho.CpuPpc64 {
+		platform = "mac_64"
+	}
+	return Binary{Path: name, Platform: platform, Library: fileType != macho.TypeExec}, true
+}
+
+// Platforms returns the platforms the executables in a build run on, named like the os fields of a build.
+// Known helper executables like redistributable installers are skipped, since they don't say what the game runs on.
+func Platforms(binaries []Binary) []string {
+	found := map[string]bool{}
+	for _, bin := range binaries {
+		if !bin.Library && !helperRegex.MatchString(path.Base(bin.Path)) {
+			found[bin.Platform] = true
+		}
+	}
+
+	platforms := []string{}
+	for _, platform := range []string{"windows", "windows_64", "mac", "mac_64", "linux", "linux_64"} {
+		if found[platform] {
+			platforms = append(platforms, platform)
+		}
+	}
+	return platforms
+}
+
+// LaunchOptions suggests the main executable of a build for every platform it runs on.
+// Executables closest to the root of the build are preferred, and known helper executables like crash handlers are skipped.
+func LaunchOptions(binaries []Binary) []models.GameBuildLaunchOption {
+	candidates := map[string][]string{}
+	for _, bin := range binaries {
+		if bin.Library || helperRegex.MatchString(path.Base(bin.Path)) {
+			continue
+		}
+		candidates[bin.Platform] = append(candidates[bin.Platform], bin.Path)
+	}
+
+	options := []models.GameBuildLaunchOption{}
+	for _, platform := range Platforms(binaries) {
+		paths := candidates[platform]
+		if len(paths) == 0 {
+			continue
+		}
+
+		sort.Slice(paths, func(i, j int) bool {
+			depthI, depthJ := strings.Count(paths[i], "/"), strings.Count(paths[j], "/")
+			if depthI != depthJ {
+				return depthI < depthJ
+			}
+			return paths[i] < paths[j]
+		})
+		options = append(options, models.GameBuildLaunchOption{OS: platform, ExecutablePath: paths[0]})
+	}
+	return options
+}
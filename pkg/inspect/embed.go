@@ -0,0 +1,103 @@
+package inspect
+
+import (
+	"archive/zip"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// canvasRegex matches canvas tags, which Unity templates and custom Godot html shells give the size of the game
+	canvasRegex = regexp.MustCompile(`(?is)<canvas\b[^>]*>`)
+
+	// unityContainerRegex matches the container div the templates of Unity 2019 and older size the game with
+	unityContainerRegex = regexp.MustCompile(`(?is)<div\b[^>]*\bid\s*=\s*["']?(?:unityContainer|gameContainer)\b[^>]*>`)
+
+	// attributeSizeRegex matches width and height attributes, like width="960" or width=960
+	attributeSizeRegex = regexp.MustCompile(`(?i)\b(width|height)\s*=\s*["']?(\d+)["']?(?:[\s/>]|$)`)
+
+	// styleSizeRegex matches width and height in inline styles, like style="width: 960px; height: 600px"
+	styleSizeRegex = regexp.MustCompile(`(?i)(?:^|[;"'\s])(width|height)\s*:\s*(\d+)px`)
+
+	// scriptSizeRegex matches the size Unity templates since 2020 set in their script, like canvas.style.width = "960px"
+	scriptSizeRegex = regexp.MustCompile(`(?i)\bcanvas\.style\.(width|height)\s*=\s*["'](\d+)px["']`)
+)
+
+// EmbedSize is the size a browser build is meant to be embedded at
+type EmbedSize struct {
+	Width  int
+	Height int
+
+	// Source describes where in the build the size was found
+	Source string
+}
+
+// DetectEmbedSize finds the size a zipped browser build is meant to be embedded at, from the canvas or container
+// in its index.html. Returns nil if the build doesn't set a fixed size, like Godot exports that fill the window.
+func DetectEmbedSize(zipPath string) (*EmbedSize, error) {
+	archive, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open the browser build as a zip: %w", err)
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		if strings.TrimPrefix(file.Name, "./") != "index.html" || file.UncompressedSize64 > maxScannedFileSize {
+			continue
+		}
+
+		content, err := readZipFile(file)
+		if err != nil {
+			return nil, err
+		}
+		return parseEmbedSize(string(content)), nil
+	}
+	return nil, nil
+}
+
+// parseEmbedSize finds the size of the game in the html of a browser build
+func parseEmbedSize(html string) *EmbedSize {
+	for _, tag := range canvasRegex.FindAllString(html, -1) {
+		if width, height := tagSize(tag); width > 0 && height > 0 {
+			return &EmbedSize{Width: width, Height: height, Source: "the canvas in index.html"}
+		}
+	}
+
+	if width, height := matchSize(scriptSizeRegex, html); width > 0 && height > 0 {
+		return &EmbedSize{Width: width, Height: height, Source: "the Unity canvas size in index.html"}
+	}
+
+	for _, tag := range unityContainerRegex.FindAllString(html, -1) {
+		if width, height := tagSize(tag); width > 0 && height > 0 {
+			return &EmbedSize{Width: width, Height: height, Source: "the Unity container in index.html"}
+		}
+	}
+	return nil
+}
+
+// tagSize reads the size of an html tag from its width and height attributes or its inline style
+func tagSize(tag string) (width, height int) {
+	width, height = matchSize(attributeSizeRegex, tag)
+	if width > 0 && height > 0 {
+		return width, height
+	}
+	return matchSize(styleSizeRegex, tag)
+}
+
+func matchSize(regex *regexp.Regexp, text string) (width, height int) {
+	for _, match := range regex.FindAllStringSubmatch(text, -1) {
+		value, err := strconv.Atoi(match[2])
+		if err != nil {
+			continue
+		}
+
+		if strings.EqualFold(match[1], "width") && width == 0 {
+			width = value
+		} else if strings.EqualFold(match[1], "height") && height == 0 {
+			height = value
+		}
+	}
+	return width, height
+}
@@ -0,0 +1,130 @@
+package inspect
+
+import (
+	"archive/zip"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// unityCompressedRegex matches the files of Unity WebGL exports that were compressed without decompression fallback,
+// like Build/game.data.br or Build/game.framework.js.gz
+var unityCompressedRegex = regexp.MustCompile(`(?i)\.(data|framework\.js|wasm|symbols\.json)\.(br|gz)$`)
+
+// godotThreadsRegex matches the flag Godot web exports since 4.3 set in their html to tell whether they use threads
+var godotThreadsRegex = regexp.MustCompile(`GODOT_THREADS_ENABLED\s*=\s*(true|false)`)
+
+// compressionNames are the names of the compression formats engines use, by file extension
+var compressionNames = map[string]string{
+	".br": "Brotli",
+	".gz": "Gzip",
+}
+
+// checkUnity finds Unity WebGL exports that only load if the server sends Content-Encoding headers for their files
+func checkUnity(files []*zip.File) []Issue {
+	// Unity exports load the build through a loader script in the same folder
+	loaders := map[string]bool{}
+	for _, file := range files {
+		if strings.HasSuffix(strings.ToLower(file.Name), ".loader.js") {
+			loaders[path.Dir(file.Name)] = true
+		}
+	}
+
+	issues := []Issue{}
+	seen := map[string]bool{}
+	for _, file := range files {
+		if !loaders[path.Dir(file.Name)] || !unityCompressedRegex.MatchString(file.Name) {
+			continue
+		}
+
+		compression := compressionNames[strings.ToLower(path.Ext(file.Name))]
+		key := path.Dir(file.Name) + compression
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		issues = append(issues, Issue{
+			Path: file.Name,
+			Message: fmt.Sprintf("The Unity build is compressed with %s, and only loads when the server sends Content-Encoding headers for it, so it shows a black screen. "+
+				"In Player Settings > Publishing Settings, set Compression Format to Disabled, or enable Decompression Fallback", compression),
+			Warning: true,
+		})
+	}
+	return issues
+}
+
+// checkGodot finds Godot web exports that need headers or server side decompression to load
+func checkGodot(files []*zip.File) ([]Issue, error) {
+	names := map[string]bool{}
+	for _, file := range files {
+		names[strings.ToLower(file.Name)] = true
+	}
+
+	issues := []Issue{}
+	for _, file := range files {
+		// Godot exports the game as a .pck next to a .wasm and a .js of the same name
+		base := godotBase(strings.ToLower(file.Name))
+		if base == "" || !names[base+".wasm"] && !names[base+".wasm.gz"] && !names[base+".wasm.br"] {
+			continue
+		}
+
+		for _, ext := range []string{".gz", ".br"} {
+			if names[base+".wasm"+ext] || names[base+".pck"+ext] {
+				issues = append(issues, Issue{
+					Path: file.Name,
+					Message: fmt.Sprintf("The Godot build has files compressed with %s. Godot doesn't decompress them itself, so they only load when the server sends Content-Encoding headers for them. "+
+						"Upload the files exactly as Godot exported them", compressionNames[ext]),
+					Warning: true,
+				})
+				break
+			}
+		}
+
+		threads, err := godotUsesThreads(files, base, names)
+		if err != nil {
+			return nil, err
+		}
+		if threads {
+			issues = append(issues, Issue{
+				Path: file.Name,
+				Message: "The Godot build uses threads, which need SharedArrayBuffer and cross-origin isolation headers to load. " +
+					"In the Web export preset, turn off Thread Support, or pick the Regular export type in Godot 3",
+				Warning: true,
+			})
+		}
+	}
+	return issues, nil
+}
+
+// godotBase returns the path of a Godot .pck file without its extensions, or an empty string for other files
+func godotBase(name string) string {
+	for _, ext := range []string{".pck", ".pck.gz", ".pck.br"} {
+		if strings.HasSuffix(name, ext) {
+			return strings.TrimSuffix(name, ext)
+		}
+	}
+	return ""
+}
+
+// godotUsesThreads returns whether the Godot web export with the given base path uses threads.
+// Exports since Godot 4.3 tell in their html, older ones ship a worker script when they do.
+func godotUsesThreads(files []*zip.File, base string, names map[string]bool) (bool, error) {
+	for _, file := range files {
+		lower := strings.ToLower(file.Name)
+		if path.Dir(lower) != path.Dir(base) || path.Ext(lower) != ".html" || file.UncompressedSize64 > maxScannedFileSize {
+			continue
+		}
+
+		content, err := readZipFile(file)
+		if err != nil {
+			return false, err
+		}
+		if match := godotThreadsRegex.FindSubmatch(content); match != nil {
+			return string(match[1]) == "true", nil
+		}
+	}
+
+	return names[base+".worker.js"], nil
+}
@@ -0,0 +1,187 @@
+package inspect
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// MaxBrowserFileSize is the size above which a single file in a browser build is flagged.
+	// Browsers tend to fail loading files this large, especially on mobile.
+	MaxBrowserFileSize = 100 * 1024 * 1024
+
+	// MaxBrowserBuildSize is the size above which the uncompressed contents of a browser build are flagged
+	MaxBrowserBuildSize = 500 * 1024 * 1024
+
+	// maxScannedFileSize is the size above which html and css files are not scanned for absolute paths
+	maxScannedFileSize = 5 * 1024 * 1024
+)
+
+// absolutePathRegex matches asset references with absolute paths in html and css, like src="/game.js" or url(/bg.png).
+// Protocol relative urls like //cdn.example.com are not matched.
+var absolutePathRegex = regexp.MustCompile(`(?i)(?:\b(?:src|href)\s*=\s*["']|\burl\(\s*["']?)(/[^/"'\s)][^"'\s)]*)`)
+
+// Issue is a problem found in a build
+type Issue struct {
+	// Path is the path of the file in the build the issue is about, if any
+	Path string
+
+	// Message describes the issue
+	Message string
+
+	// Warning is whether the build may still run despite the issue, depending on how it is served.
+	// Builds shouldn't be refused for warnings.
+	Warning bool
+}
+
+func (i Issue) String() string {
+	if i.Path == "" {
+		return i.Message
+	}
+	return i.Path + ": " + i.Message
+}
+
+// CheckHTML5 checks a zipped HTML5 browser build for issues that keep it from running on Game Jolt.
+// Engine export settings that need special server headers are reported as warnings.
+func CheckHTML5(zipPath string) ([]Issue, error) {
+	archive, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open the browser build as a zip: %w", err)
+	}
+	defer archive.Close()
+
+	files := []*zip.File{}
+	for _, file := range archive.File {
+		if !file.FileInfo().IsDir() {
+			files = append(files, file)
+		}
+	}
+
+	issues := checkIndex(files)
+	issues = append(issues, checkCaseClashes(files)...)
+	issues = append(issues, checkSizes(files)...)
+
+	issues = append(issues, checkUnity(files)...)
+
+	godot, err := checkGodot(files)
+	if err != nil {
+		return nil, err
+	}
+	issues = append(issues, godot...)
+
+	absolutePaths, err := checkAbsolutePaths(files)
+	if err != nil {
+		return nil, err
+	}
+	return append(issues, absolutePaths...), nil
+}
+
+// checkIndex checks that there is an index.html at the root of the build
+func checkIndex(files []*zip.File) []Issue {
+	nested := []string{}
+	for _, file := range files {
+		name := strings.TrimPrefix(file.Name, "./")
+		if name == "index.html" {
+			return nil
+		}
+		if strings.EqualFold(name, "index.html") {
+			return []Issue{{Path: name, Message: "The index file has to be named index.html, all lowercase"}}
+		}
+		if strings.EqualFold(path.Base(name), "index.html") {
+			nested = append(nested, name)
+		}
+	}
+
+	// The most common mistake is zipping the export folder itself instead of its contents
+	for _, name := range nested {
+		if dir := path.Dir(name); !strings.Contains(dir, "/") {
+			return []Issue{{Path: name, Message: fmt.Sprintf("The game is nested in the %s folder. Zip the contents of %s instead of the folder itself, so that index.html is at the root of the zip", dir, dir)}}
+		}
+	}
+	return []Issue{{Message: "There is no index.html at the root of the zip"}}
+}
+
+// checkCaseClashes finds files with names that only differ in case. They overwrite each other on case insensitive file systems.
+func checkCaseClashes(files []*zip.File) []Issue {
+	byLowerName := map[string][]string{}
+	for _, file := range files {
+		lower := strings.ToLower(file.Name)
+		byLowerName[lower] = append(byLowerName[lower], file.Name)
+	}
+
+	issues := []Issue{}
+	for _, names := range byLowerName {
+		if len(names) > 1 {
+			issues = append(issues, Issue{Path: names[0], Message: fmt.Sprintf("The file names %s only differ in case", strings.Join(names, ", "))})
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Path < issues[j].Path })
+	return issues
+}
+
+// checkSizes flags files and builds that are too large to load in browsers
+func checkSizes(files []*zip.File) []Issue {
+	issues := []Issue{}
+	total := uint64(0)
+	for _, file := range files {
+		total += file.UncompressedSize64
+		if file.UncompressedSize64 > MaxBrowserFileSize {
+			issues = append(issues, Issue{Path: file.Name, Message: fmt.Sprintf("The file is %d MB, files over %d MB are likely to fail to load", file.UncompressedSize64/1024/1024, MaxBrowserFileSize/1024/1024)})
+		}
+	}
+
+	if total > MaxBrowserBuildSize {
+		issues = append(issues, Issue{Message: fmt.Sprintf("The build is %d MB uncompressed, builds over %d MB are likely to fail to load", total/1024/1024, MaxBrowserBuildSize/1024/1024)})
+	}
+	return issues
+}
+
+// checkAbsolutePaths finds assets referenced by absolute paths in html and css files.
+// Browser builds are served from a subfolder, so absolute paths point outside of the build.
+func checkAbsolutePaths(files []*zip.File) ([]Issue, error) {
+	issues := []Issue{}
+	for _, file := range files {
+		ext := strings.ToLower(path.Ext(file.Name))
+		if ext != ".html" && ext != ".htm" && ext != ".css" {
+			continue
+		}
+		if file.UncompressedSize64 > maxScannedFileSize {
+			continue
+		}
+
+		content, err := readZipFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		seen := map[string]bool{}
+		for _, match := range absolutePathRegex.FindAllStringSubmatch(string(content), -1) {
+			if seen[match[1]] {
+				continue
+			}
+			seen[match[1]] = true
+			issues = append(issues, Issue{Path: file.Name, Message: fmt.Sprintf("%s is an absolute path, it won't load once the build is hosted. Use a relative path instead", match[1])})
+		}
+	}
+	return issues, nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read %s from the zip: %w", file.Name, err)
+	}
+	defer reader.Close()
+
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read %s from the zip: %w", file.Name, err)
+	}
+	return content, nil
+}
@@ -0,0 +1,195 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"golang.org/x/term"
+)
+
+// pickerHeight is how many items the picker shows at once
+const pickerHeight = 10
+
+// ErrPickCanceled is returned when the user cancels picking an item with Escape or Ctrl+C
+var ErrPickCanceled = errors.New("Canceled")
+
+// CanPick returns whether both stdin and stdout are a terminal, so that an item can be picked from a list interactively
+func CanPick() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// Pick shows a list of items to pick one from with the arrow keys and Enter. Typing filters the list.
+// Returns the index of the picked item.
+func Pick(in *bufio.Reader, title string, items []string) (int, error) {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return 0, err
+	}
+	defer term.Restore(fd, state)
+
+	// Hide the cursor while picking
+	fmt.Fprint(color.Output, "\x1b[?25l")
+	defer fmt.Fprint(color.Output, "\x1b[?25h")
+
+	picker := &picker{title: title, items: items, width: 80}
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		picker.width = width
+	}
+	picker.filter()
+
+	for {
+		picker.render()
+
+		key, err := readKey(in)
+		if err != nil {
+			return 0, err
+		}
+
+		switch key {
+		case "\r", "\n":
+			if len(picker.matches) == 0 {
+				continue
+			}
+
+			picked := picker.matches[picker.cursor]
+			picker.clear()
+			fmt.Fprintf(color.Output, "%s %s\r\n", title, items[picked])
+			return picked, nil
+		case "\x03", "\x1b":
+			picker.clear()
+			return 0, ErrPickCanceled
+		case "up":
+			if picker.cursor > 0 {
+				picker.cursor--
+			}
+		case "down":
+			if picker.cursor < len(picker.matches)-1 {
+				picker.cursor++
+			}
+		case "\x7f", "\b":
+			if len(picker.query) > 0 {
+				_, size := utf8.DecodeLastRuneInString(picker.query)
+				picker.query = picker.query[:len(picker.query)-size]
+				picker.filter()
+			}
+		default:
+			if key >= " " && key != "\x7f" && utf8.ValidString(key) {
+				picker.query += key
+				picker.filter()
+			}
+		}
+	}
+}
+
+type picker struct {
+	title   string
+	items   []string
+	width   int
+	query   string
+	matches []int
+	cursor  int
+	lines   int
+}
+
+// filter updates the items matching the query
+func (p *picker) filter() {
+	query := strings.ToLower(p.query)
+
+	p.matches = p.matches[:0]
+	for i, item := range p.items {
+		if strings.Contains(strings.ToLower(item), query) {
+			p.matches = append(p.matches, i)
+		}
+	}
+	p.cursor = 0
+}
+
+// clear erases the picker from the terminal
+func (p *picker) clear() {
+	if p.lines > 1 {
+		fmt.Fprintf(color.Output, "\x1b[%dA", p.lines-1)
+	}
+	fmt.Fprint(color.Output, "\r\x1b[J")
+	p.lines = 0
+}
+
+// render draws the picker, replacing the previous drawing
+func (p *picker) render() {
+	p.clear()
+
+	PromptCol(color.Output, "%s ", p.title)
+	fmt.Fprint(color.Output, p.truncate(p.query, len(p.title)+1))
+	p.lines = 1
+
+	if len(p.matches) == 0 {
+		WarnCol(color.Output, "\r\n  No matches")
+		p.lines++
+	}
+
+	// Scroll so that the cursor stays in view
+	start := 0
+	if p.cursor >= pickerHeight {
+		start = p.cursor - pickerHeight + 1
+	}
+	for i := start; i < len(p.matches) && i < start+pickerHeight; i++ {
+		item := p.truncate(p.items[p.matches[i]], 2)
+		if i == p.cursor {
+			InfoCol(color.Output, "\r\n> %s", item)
+		} else {
+			fmt.Fprintf(color.Output, "\r\n  %s", item)
+		}
+		p.lines++
+	}
+}
+
+// truncate shortens text so that it fits on a line after the given indent, to keep lines from wrapping
+func (p *picker) truncate(text string, indent int) string {
+	max := p.width - indent - 1
+	if max < 1 || utf8.RuneCountInString(text) <= max {
+		return text
+	}
+
+	runes := []rune(text)
+	return string(runes[:max-1]) + "…"
+}
+
+// readKey reads a single key press. Arrow keys are returned as "up" and "down".
+func readKey(in *bufio.Reader) (string, error) {
+	r, _, err := in.ReadRune()
+	if err != nil {
+		return "", err
+	}
+
+	// Escape sequences arrive all at once, a lone escape is the Escape key
+	if r != '\x1b' || in.Buffered() == 0 {
+		return string(r), nil
+	}
+
+	sequence := []byte{}
+	for in.Buffered() > 0 {
+		b, err := in.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		sequence = append(sequence, b)
+
+		// Sequences end with a letter or a tilde, for example "[A" or "[3~"
+		if len(sequence) > 1 && (b >= 'A' && b <= 'Z' || b >= 'a' && b <= 'z' || b == '~') {
+			break
+		}
+	}
+
+	switch string(sequence) {
+	case "[A", "OA":
+		return "up", nil
+	case "[B", "OB":
+		return "down", nil
+	}
+	return "", nil
+}
@@ -0,0 +1,199 @@
+package update
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	semver "github.com/blang/semver/v4"
+)
+
+// ErrChecksumMismatch is returned when a downloaded binary doesn't match its published checksum
+var ErrChecksumMismatch = errors.New("The downloaded binary doesn't match its published checksum")
+
+// Index is the release index listing the latest version and its binaries.
+// Relative urls are resolved against the url of the index itself.
+type Index struct {
+	Version   string            `json:"version"`
+	Checksums string            `json:"checksums"`
+	Binaries  map[string]string `json:"binaries"`
+
+	url *url.URL
+}
+
+// Platform returns the key of the current platform in the index binaries, for example "linux-amd64"
+func Platform() string {
+	return runtime.GOOS + "-" + runtime.GOARCH
+}
+
+// FetchIndex fetches the release index from the given url
+func FetchIndex(client *http.Client, indexURL string) (*Index, error) {
+	base, err := url.Parse(indexURL)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid release index url: %w", err)
+	}
+
+	body, err := fetch(client, indexURL)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch the release index: %w", err)
+	}
+	defer body.Close()
+
+	index := &Index{url: base}
+	if err = json.NewDecoder(body).Decode(index); err != nil {
+		return nil, fmt.Errorf("Failed to read the release index: %w", err)
+	}
+	return index, nil
+}
+
+// Semver returns the parsed version of the latest release
+func (i *Index) Semver() (semver.Version, error) {
+	version, err := semver.ParseTolerant(i.Version)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("The release index has an invalid version: %s", i.Version)
+	}
+	return version, nil
+}
+
+// BinaryURL returns the url of the binary for the given platform
+func (i *Index) BinaryURL(platform string) (string, error) {
+	binary, ok := i.Binaries[platform]
+	if !ok {
+		return "", fmt.Errorf("There is no binary for %s in the latest release", platform)
+	}
+	return i.resolve(binary)
+}
+
+// Checksum fetches the published checksum list and returns the sha256 checksum of the binary at the given url.
+// The checksum list has the format of sha256sum, a checksum and a file name per line.
+func (i *Index) Checksum(client *http.Client, binaryURL string) (string, error) {
+	if i.Checksums == "" {
+		return "", errors.New("The release index has no checksum list, refusing to update")
+	}
+
+	checksumsURL, err := i.resolve(i.Checksums)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := fetch(client, checksumsURL)
+	if err != nil {
+		return "", fmt.Errorf("Failed to fetch the checksum list: %w", err)
+	}
+	defer body.Close()
+
+	binary, err := url.Parse(binaryURL)
+	if err != nil {
+		return "", err
+	}
+	name := path.Base(binary.Path)
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return "", fmt.Errorf("Failed to read the checksum list: %w", err)
+	}
+
+	return "", fmt.Errorf("There is no checksum for %s in the checksum list, refusing to update", name)
+}
+
+func (i *Index) resolve(ref string) (string, error) {
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("Invalid url in the release index: %w", err)
+	}
+	if i.url == nil {
+		return refURL.String(), nil
+	}
+	return i.url.ResolveReference(refURL).String(), nil
+}
+
+// Download downloads the binary at the given url next to the executable it will replace,
+// and verifies it against the given sha256 checksum. Returns the path of the downloaded binary.
+func Download(client *http.Client, binaryURL, checksum, executable string) (string, error) {
+	body, err := fetch(client, binaryURL)
+	if err != nil {
+		return "", fmt.Errorf("Failed to download the binary: %w", err)
+	}
+	defer body.Close()
+
+	// Download to the same folder so the executable can be replaced with a rename
+	file, err := ioutil.TempFile(filepath.Dir(executable), "."+filepath.Base(executable)+".*")
+	if err != nil {
+		return "", fmt.Errorf("Failed to download the binary: %w", err)
+	}
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, hash), body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("Failed to download the binary: %w", err)
+	}
+
+	if hex.EncodeToString(hash.Sum(nil)) != checksum {
+		os.Remove(file.Name())
+		return "", ErrChecksumMismatch
+	}
+	return file.Name(), nil
+}
+
+// Replace replaces the executable with the given binary.
+// The binary is moved over the executable, so the executable is never left half written.
+func Replace(executable, binary string) error {
+	stat, err := os.Stat(executable)
+	if err != nil {
+		return err
+	}
+	if err = os.Chmod(binary, stat.Mode()); err != nil {
+		return err
+	}
+
+	if runtime.GOOS != "windows" {
+		return os.Rename(binary, executable)
+	}
+
+	// Windows doesn't allow replacing a running executable, but it does allow renaming it
+	old := executable + ".old"
+	os.Remove(old)
+	if err = os.Rename(executable, old); err != nil {
+		return err
+	}
+	if err = os.Rename(binary, executable); err != nil {
+		os.Rename(old, executable)
+		return err
+	}
+	return nil
+}
+
+func fetch(client *http.Client, target string) (io.ReadCloser, error) {
+	res, err := client.Get(target)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("%s responded with %s", target, res.Status)
+	}
+	return res.Body, nil
+}